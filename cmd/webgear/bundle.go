@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"text/template"
+)
+
+// bundleMain is the program we generate and run to bundle a Gear.
+var bundleMain = template.Must(template.New("bundleMain").Parse(`
+// Code generated by "webgear bundle". DO NOT EDIT.
+
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/johnsiilver/webgear/component"
+
+	gear {{printf "%q" .Pkg}}
+)
+
+func main() {
+	g, err := gear.{{.Func}}()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	out := {{printf "%q" .Out}}
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		log.Fatal(err)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := component.Bundle(context.Background(), g, f); err != nil {
+		f.Close()
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
+`))
+
+func runBundle(args []string) error {
+	fs := flag.NewFlagSet("bundle", flag.ExitOnError)
+	pkg := fs.String("pkg", "", "The import path of the package that provides the *component.Gear")
+	fn := fs.String("func", "NewGear", "The function in -pkg with signature func() (*component.Gear, error)")
+	out := fs.String("out", "", "The file to write the Javascript module to")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n\twebgear bundle -pkg <import path> [-func NewGear] -out <file.js>\n\n")
+		fmt.Fprintf(fs.Output(), "Must be run inside the Go module that contains -pkg.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *pkg == "" || *out == "" {
+		fs.Usage()
+		return fmt.Errorf("-pkg and -out must be provided")
+	}
+
+	absOut, err := filepath.Abs(*out)
+	if err != nil {
+		return err
+	}
+
+	err = runProgram(
+		"bundle",
+		bundleMain,
+		struct{ Pkg, Func, Out string }{*pkg, *fn, absOut},
+	)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("component bundled to %s\n", absOut)
+	return nil
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"text/template"
+)
+
+// exportMain is the program we generate and run to do an export. As Go cannot load the user's
+// *handlers.Mux at runtime, we compile a program that imports their package.
+var exportMain = template.Must(template.New("exportMain").Parse(`
+// Code generated by "webgear export". DO NOT EDIT.
+
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/johnsiilver/webgear/export"
+
+	site {{printf "%q" .Pkg}}
+)
+
+func main() {
+	m, err := site.{{.Func}}()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	urls := []string{
+		{{- range .URLs}}
+		{{printf "%q" .}},
+		{{- end}}
+	}
+
+	if err := export.Export(context.Background(), m, {{printf "%q" .Out}}, export.ExtraURLs(urls...)); err != nil {
+		log.Fatal(err)
+	}
+}
+`))
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	pkg := fs.String("pkg", "", "The import path of the package that provides the *handlers.Mux")
+	fn := fs.String("func", "NewMux", "The function in -pkg with signature func() (*handlers.Mux, error)")
+	out := fs.String("out", "public", "The directory to write the site to")
+	urls := stringList{}
+	fs.Var(&urls, "url", "A URL path to render in addition to the registered patterns. Can be passed multiple times")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n\twebgear export -pkg <import path> [-func NewMux] [-out public] [-url /path ...]\n\n")
+		fmt.Fprintf(fs.Output(), "Must be run inside the Go module that contains -pkg.\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *pkg == "" {
+		fs.Usage()
+		return fmt.Errorf("-pkg must be provided")
+	}
+
+	absOut, err := filepath.Abs(*out)
+	if err != nil {
+		return err
+	}
+
+	err = runProgram(
+		"export",
+		exportMain,
+		struct {
+			Pkg, Func, Out string
+			URLs           []string
+		}{*pkg, *fn, absOut, urls},
+	)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("site exported to %s\n", absOut)
+	return nil
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	stdhtml "html"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// htmlPkg is the import path of the html package, which all generated types are in.
+const htmlPkg = "github.com/johnsiilver/webgear/html"
+
+func runHTML2Go(args []string) error {
+	fs := flag.NewFlagSet("html2go", flag.ExitOnError)
+	in := fs.String("in", "", "The HTML file to convert")
+	out := fs.String("out", "", "The Go file to write. If not set, the Go source is written to stdout")
+	pkg := fs.String("pkg", "main", "The package name for the Go file")
+	fn := fs.String("func", "NewDoc", "The name of the function that returns the *html.Doc")
+	fragment := fs.Bool("fragment", false, "Treat -in as part of a <body> and have -func return []html.Element")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage:\n\twebgear html2go -in <file.html> [-out <file.go>] [-pkg main] [-func NewDoc] [-fragment]\n\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *in == "" {
+		fs.Usage()
+		return fmt.Errorf("-in must be provided")
+	}
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	src, err := html2Go(f, filepath.Base(*in), *pkg, *fn, *fragment)
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*out, src, 0644)
+}
+
+// html2Go parses the HTML in r and returns gofmt'ed Go source for a file in package pkg with a function called
+// fn that constructs it. name is the name of the HTML file, which is used in comments.
+func html2Go(r io.Reader, name, pkg, fn string, fragment bool) ([]byte, error) {
+	var (
+		v      interface{}
+		result string
+	)
+	if fragment {
+		elements, err := html.ParseFragment(r)
+		if err != nil {
+			return nil, err
+		}
+		v, result = elements, "[]html.Element"
+	} else {
+		doc, err := html.Parse(r)
+		if err != nil {
+			return nil, err
+		}
+		v, result = doc, "*html.Doc"
+	}
+
+	g := &goGen{}
+	if err := g.value(reflect.ValueOf(v), false); err != nil {
+		return nil, err
+	}
+
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "// Generated by \"webgear html2go\" from %s.\n\n", name)
+	fmt.Fprintf(buff, "package %s\n\n", pkg)
+	fmt.Fprintf(buff, "import %q\n\n", htmlPkg)
+	fmt.Fprintf(buff, "// %s returns the content of %s.\n", fn, name)
+	fmt.Fprintf(buff, "func %s() %s {\n\treturn %s\n}\n", fn, result, g.buff.String())
+
+	src, err := format.Source(buff.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated Go source could not be formatted: %w", err)
+	}
+	return src, nil
+}
+
+// goGen writes Go expressions that construct values from the html package.
+type goGen struct {
+	buff bytes.Buffer
+}
+
+// value writes an expression for v. If typed is true, v is being assigned to an interface and
+// constants must be converted to their type.
+func (g *goGen) value(v reflect.Value, typed bool) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			g.buff.WriteString("nil")
+			return nil
+		}
+		return g.value(v.Elem(), true)
+	case reflect.Ptr:
+		if v.IsNil() {
+			g.buff.WriteString("nil")
+			return nil
+		}
+		if u, ok := v.Interface().(*url.URL); ok {
+			fmt.Fprintf(&g.buff, "html.URLParse(%s)", quote(u.String()))
+			return nil
+		}
+		g.buff.WriteString("&")
+		return g.value(v.Elem(), false)
+	case reflect.Struct:
+		return g.structLit(v)
+	case reflect.Slice:
+		name, err := typeName(v.Type())
+		if err != nil {
+			return err
+		}
+		g.buff.WriteString(name + "{\n")
+		for i := 0; i < v.Len(); i++ {
+			var err error
+			elem := v.Index(i)
+			switch {
+			case elem.Kind() == reflect.Struct:
+				// The type can be left out of composite literals in a slice, as gofmt -s does.
+				err = g.fields(elem)
+			case elem.Kind() == reflect.Ptr && !elem.IsNil() && elem.Elem().Kind() == reflect.Struct && elem.Type() != reflect.TypeOf(&url.URL{}):
+				err = g.fields(elem.Elem())
+			default:
+				err = g.value(elem, v.Type().Elem().Kind() == reflect.Interface)
+			}
+			if err != nil {
+				return err
+			}
+			g.buff.WriteString(",\n")
+		}
+		g.buff.WriteString("}")
+		return nil
+	case reflect.Map:
+		name, err := typeName(v.Type())
+		if err != nil {
+			return err
+		}
+		keys := v.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+
+		g.buff.WriteString(name + "{\n")
+		for _, k := range keys {
+			if err := g.value(k, false); err != nil {
+				return err
+			}
+			g.buff.WriteString(": ")
+			if err := g.value(v.MapIndex(k), false); err != nil {
+				return err
+			}
+			g.buff.WriteString(",\n")
+		}
+		g.buff.WriteString("}")
+		return nil
+	case reflect.String:
+		// html.Parse() escapes text, so it is written as the text the page shows, which reads like the HTML file.
+		if t, ok := v.Interface().(html.TextElement); ok {
+			if s := stdhtml.UnescapeString(string(t)); s != string(t) && html.EscapedText(s) == t {
+				fmt.Fprintf(&g.buff, "html.EscapedText(%s)", quote(s))
+				return nil
+			}
+		}
+		return g.constant(v, quote(v.String()), typed)
+	case reflect.Bool:
+		return g.constant(v, strconv.FormatBool(v.Bool()), typed)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() == html.Zero {
+			g.buff.WriteString("html.Zero")
+			return nil
+		}
+		return g.constant(v, strconv.FormatInt(v.Int(), 10), typed)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return g.constant(v, strconv.FormatUint(v.Uint(), 10), typed)
+	}
+	return fmt.Errorf("cannot generate Go for a value of type %s", v.Type())
+}
+
+// structLit writes a composite literal for the struct v, leaving out fields with zero values.
+func (g *goGen) structLit(v reflect.Value) error {
+	name, err := typeName(v.Type())
+	if err != nil {
+		return err
+	}
+	g.buff.WriteString(name)
+	return g.fields(v)
+}
+
+// fields writes the braces and fields of a composite literal for the struct v.
+func (g *goGen) fields(v reflect.Value) error {
+	g.buff.WriteString("{\n")
+
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		field := v.Field(i)
+		if sf.PkgPath != "" || field.IsZero() {
+			continue
+		}
+		g.buff.WriteString(sf.Name + ": ")
+		if err := g.value(field, false); err != nil {
+			return fmt.Errorf("%s.%s: %w", t.Name(), sf.Name, err)
+		}
+		g.buff.WriteString(",\n")
+	}
+	g.buff.WriteString("}")
+	return nil
+}
+
+// constant writes the literal s. If typed, the literal is converted to v's type.
+func (g *goGen) constant(v reflect.Value, s string, typed bool) error {
+	if !typed || v.Type().Name() == v.Kind().String() {
+		g.buff.WriteString(s)
+		return nil
+	}
+	name, err := typeName(v.Type())
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(&g.buff, "%s(%s)", name, s)
+	return nil
+}
+
+// typeName returns the name of t as it is written in Go source that imports the html package.
+func typeName(t reflect.Type) (string, error) {
+	switch t.Kind() {
+	case reflect.Ptr:
+		name, err := typeName(t.Elem())
+		return "*" + name, err
+	case reflect.Slice:
+		name, err := typeName(t.Elem())
+		return "[]" + name, err
+	case reflect.Map:
+		key, err := typeName(t.Key())
+		if err != nil {
+			return "", err
+		}
+		elem, err := typeName(t.Elem())
+		return "map[" + key + "]" + elem, err
+	}
+
+	switch t.PkgPath() {
+	case "":
+		return t.Name(), nil
+	case htmlPkg:
+		return "html." + t.Name(), nil
+	}
+	return "", fmt.Errorf("cannot generate Go for type %s from package %s", t.Name(), t.PkgPath())
+}
+
+// quote returns s as a Go string literal. Strings with multiple lines are raw strings when possible.
+func quote(s string) string {
+	if strings.Contains(s, "\n") && !strings.Contains(s, "`") && !strings.Contains(s, "\r") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
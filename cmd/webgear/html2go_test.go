@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/johnsiilver/webgear/html"
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestHTML2Go(t *testing.T) {
+	tests := []struct {
+		desc     string
+		html     string
+		fragment bool
+		want     []string
+	}{
+		{
+			desc: "Doc",
+			html: `<html lang="en"><head><title>Hi</title></head><body><p>Hello</p></body></html>`,
+			want: []string{
+				"package pages",
+				`import "github.com/johnsiilver/webgear/html"`,
+				"func NewDoc() *html.Doc {",
+				`&html.Title{
+					TagValue: "Hi",
+				}`,
+				`html.TextElement("Hello")`,
+				`Lang: "en"`,
+			},
+		},
+		{
+			desc:     "Fragment",
+			html:     `<a href="/x">x</a><table><tr><td colspan="2">y</td></tr></table><section id="s" x-y="2"></section><p data-x="1">z</p>`,
+			fragment: true,
+			want: []string{
+				"func NewDoc() []html.Element {",
+				`Href: html.URLParse("/x")`,
+				"Elements: []*html.TR{\n{",
+				"ColSpan: 2,",
+				`Name: "section"`,
+				`GlobalAttrs: html.GlobalAttrs{ ID: "s", }`,
+				"Attrs: []html.Attr{\n{",
+				`Data: map[string]string{ "x": "1", }`,
+			},
+		},
+		{
+			desc:     "Escaped text",
+			html:     `<p>a &amp; b</p>`,
+			fragment: true,
+			want:     []string{`html.EscapedText("a & b")`},
+		},
+	}
+
+	for _, test := range tests {
+		src, err := html2Go(strings.NewReader(test.html), "page.html", "pages", "NewDoc", test.fragment)
+		if err != nil {
+			t.Errorf("TestHTML2Go(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+
+		got := strings.Join(strings.Fields(string(src)), " ")
+		for _, want := range test.want {
+			want = strings.Join(strings.Fields(want), " ")
+			if !strings.Contains(got, want) {
+				t.Errorf("TestHTML2Go(%s): output did not contain %q:\n%s", test.desc, want, src)
+			}
+		}
+	}
+}
+
+// TestHTML2GoText checks that the text in the generated Go is the same as the text html.ParseFragment() returns,
+// so that the page renders the same.
+func TestHTML2GoText(t *testing.T) {
+	const page = `<p>a &amp; b &lt;c&gt; &quot;d&quot;</p><p>plain</p><script>if (a < b && c) {}</script>`
+
+	src, err := html2Go(strings.NewReader(page), "page.html", "pages", "NewDoc", true)
+	if err != nil {
+		t.Fatalf("TestHTML2GoText: got err == %s, want err == nil", err)
+	}
+	f, err := parser.ParseFile(token.NewFileSet(), "page.go", src, 0)
+	if err != nil {
+		t.Fatalf("TestHTML2GoText: generated Go did not parse: %s", err)
+	}
+
+	// Evaluate the html.EscapedText() and html.TextElement() calls in the generated Go.
+	var got []html.TextElement
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) != 1 {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		lit, isLit := call.Args[0].(*ast.BasicLit)
+		if !ok || !isLit {
+			return true
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			t.Fatalf("TestHTML2GoText: could not unquote %s: %s", lit.Value, err)
+		}
+		switch sel.Sel.Name {
+		case "EscapedText":
+			got = append(got, html.EscapedText(s))
+		case "TextElement":
+			got = append(got, html.TextElement(s))
+		}
+		return true
+	})
+
+	elements, err := html.ParseFragment(strings.NewReader(page))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want []html.TextElement
+	for _, e := range elements {
+		for walked := range html.Walker(context.Background(), e) {
+			if t, ok := walked.Element.(html.TextElement); ok {
+				want = append(want, t)
+			}
+		}
+	}
+
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("TestHTML2GoText: -want/+got:\n%s\n%s", diff, src)
+	}
+}
@@ -0,0 +1,62 @@
+/*
+The webgear command provides tooling for sites built with webgear.
+
+Usage:
+	webgear <command> [arguments]
+
+The commands are:
+	export    renders a site built on handlers.Mux into a directory of static files
+	bundle    writes a component.Gear as a standalone Javascript module
+	html2go   converts an HTML file into Go that constructs it with the html package
+
+Use "webgear <command> -h" for more information about a command.
+*/
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// command is a webgear sub-command. args does not include the command name.
+type command struct {
+	name  string
+	short string
+	run   func(args []string) error
+}
+
+var commands = []command{
+	{name: "export", short: "renders a site built on handlers.Mux into a directory of static files", run: runExport},
+	{name: "bundle", short: "writes a component.Gear as a standalone Javascript module", run: runBundle},
+	{name: "html2go", short: "converts an HTML file into Go that constructs it with the html package", run: runHTML2Go},
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "Usage:\n\twebgear <command> [arguments]\n\nThe commands are:\n")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "\t%-10s%s\n", c.name, c.short)
+	}
+	fmt.Fprintf(os.Stderr, "\nUse \"webgear <command> -h\" for more information about a command.\n")
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	for _, c := range commands {
+		if c.name != os.Args[1] {
+			continue
+		}
+		if err := c.run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "webgear %s: %s\n", c.name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "webgear: unknown command %q\n\n", os.Args[1])
+	usage()
+	os.Exit(2)
+}
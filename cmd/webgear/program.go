@@ -0,0 +1,57 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// stringList implements flag.Value to allow a flag to be passed multiple times.
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// runProgram generates a main package from tmpl and data, then runs it with "go run". As Go cannot load
+// the user's code at runtime, commands compile a program that imports their package. This must be run
+// inside the Go module that contains the user's package.
+func runProgram(name string, tmpl *template.Template, data interface{}) error {
+	buff := &bytes.Buffer{}
+	if err := tmpl.Execute(buff, data); err != nil {
+		return err
+	}
+	src, err := format.Source(buff.Bytes())
+	if err != nil {
+		return fmt.Errorf("generated bad %s program: %w", name, err)
+	}
+
+	// This must be inside the current module so that it can import the user's package.
+	dir, err := os.MkdirTemp(".", ".webgear-"+name+"-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), src, 0644); err != nil {
+		return err
+	}
+
+	cmd := exec.Command("go", "run", "./"+filepath.ToSlash(dir))
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s program failed: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package component
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// Bundle writes a self-contained Javascript module to w that defines the Gear's custom element. This includes
+// the Gear's template, loader and StyleSheets along with those of every Gear added with AddGear(). This allows
+// the component to be used on pages not rendered by webgear with:
+//
+//	<script type="module" src="/js/my-component.js"></script>
+//	<my-component></my-component>
+//
+// The template is rendered once when Bundle() is called. DataFuncs and html.Dynamic elements receive a GET
+// request for "/" using ctx. Declarative shadow roots are not available in a bundle.
+func Bundle(ctx context.Context, g *Gear, w io.Writer) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("Bundle(%s): %v", g.name, r)
+		}
+	}()
+
+	req, err := http.NewRequest(http.MethodGet, "/", nil)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	pipe := html.NewPipeline(ctx, req, nil)
+
+	templates := []string{}
+	scripts := &bytes.Buffer{}
+	err = g.render(
+		pipe,
+		false,
+		func(sheet *StyleSheet) error {
+			return executeScript(scripts, "sheet", sheet)
+		},
+		func(pipe html.Pipeline) error {
+			buff := &bytes.Buffer{}
+			pipe.W = buff
+			if err := gearTmpl.ExecuteTemplate(buff, "template", pipe); err != nil {
+				return err
+			}
+			templates = append(templates, strings.TrimSpace(buff.String()))
+			return executeScript(scripts, "script", pipe)
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("Bundle(%s): %w", g.name, err)
+	}
+	if err := pipe.HadError(); err != nil {
+		return fmt.Errorf("Bundle(%s): %w", g.name, err)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(templates, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by webgear for the <%s> custom element. DO NOT EDIT.\n\n", g.name)
+	fmt.Fprintf(out, "const templates = %s;\n", b)
+	out.WriteString("for (const t of templates) {\n\tdocument.body.insertAdjacentHTML('beforeend', t);\n}\n")
+	out.Write(scripts.Bytes())
+
+	_, err = w.Write(out.Bytes())
+	return err
+}
+
+// executeScript executes the named template, which outputs a <script>, and writes the content of the script to w.
+func executeScript(w io.Writer, name string, data interface{}) error {
+	buff := &bytes.Buffer{}
+	if err := gearTmpl.ExecuteTemplate(buff, name, data); err != nil {
+		return err
+	}
+	s := strings.TrimSpace(buff.String())
+	s = strings.TrimPrefix(s, "<script>")
+	s = strings.TrimSuffix(s, "</script>")
+	_, err := fmt.Fprintf(w, "\n%s\n", strings.TrimSpace(s))
+	return err
+}
@@ -75,6 +75,124 @@ If you don't know about web components, a good introduction can be found here: h
 	},
 */
 //
+// Declarative Shadow DOM
+//
+/*
+By default a component's shadow DOM is built by Javascript after the page loads. Passing the DeclarativeShadow()
+option to New() renders the shadow DOM inside every html.Component tag on the server, so the component displays
+even if Javascript is disabled. The Gear must still be added to the page, as its template and loader are
+used when the browser does not support declarative shadow roots.
+
+	gear, err := component.New("print-name-author", doc, component.DeclarativeShadow())
+*/
+//
+// Slots
+//
+/*
+A Gear's Doc can contain html.Slot elements that display content placed inside the html.Component tag. This
+allows building layout components, such as cards or dialogs, that wrap content provided by the page. Content
+with GlobalAttrs.Slot set is displayed in the html.Slot with the same Name. All other content is displayed in
+the html.Slot without a Name.
+
+	// Inside the Gear's Doc.
+	&html.Div{
+		Elements: []html.Element{
+			&html.Slot{Name: "title"},
+			&html.Slot{},
+		},
+	},
+
+	// Inside the page.
+	&html.Component{
+		Gear: card,
+		Elements: []html.Element{
+			&html.H{Level: 2, GlobalAttrs: html.GlobalAttrs{Slot: "title"}, Elements: []html.Element{html.TextElement("Title")}},
+			&html.P{Elements: []html.Element{html.TextElement("The card's content.")}},
+		},
+	}
+*/
+//
+// Shadow Root Options
+//
+/*
+By default the shadow root is open, so the host page can reach the component's internals with
+element.shadowRoot. ClosedShadow() prevents this, which is useful for widgets embedded in pages you don't
+control. DelegatesFocus() and AssignSlots() set the matching attachShadow() options.
+
+	gear, err := component.New("my-widget", doc, component.ClosedShadow(), component.DelegatesFocus())
+*/
+//
+// Lazy Loading
+//
+/*
+The Lazy() option outputs a small script in place of the Gear's template and loader. The Gear, and every Gear
+added to it with AddGear(), is fetched when an instance of the component scrolls into view or is interacted
+with. handlers.Mux.Handle() registers the endpoint that serves the Gear automatically.
+
+	gear, err := component.New("comment-thread", doc, component.Lazy())
+*/
+//
+// Shared Styles
+//
+/*
+An html.Style or html.Link in a Gear's Doc is copied into every instance of the component. SharedStyle() instead
+outputs the CSS once per page and shares a single constructable stylesheet between all instances. A StyleSheet
+made with NewStyleSheet() can be shared by many Gears with AdoptStyleSheets(), such as for design tokens.
+
+	tokens, err := component.NewStyleSheet("tokens", ":host { --primary: #336; }")
+	if err != nil {
+		// Do something
+	}
+
+	gear, err := component.New(
+		"my-card",
+		doc,
+		component.AdoptStyleSheets(tokens),
+		component.SharedStyle("div { color: var(--primary); }"),
+	)
+*/
+//
+// Props
+//
+/*
+The Props() option declares the attributes a Gear observes. In the browser, each Prop is exposed as a property
+on the custom element and changes to the attribute update any PropText() elements and dispatch a
+"webgear-prop-changed" event. Attribute values on the html.Component are validated when the page is rendered.
+
+	gear, err := component.New(
+		"user-card",
+		doc, // Contains component.PropText("user-name").
+		component.DeclarativeShadow(),
+		component.Props(
+			component.Prop{Name: "user-name", Default: "anonymous"},
+			component.Prop{Name: "max-items", Type: component.NumberProp, Default: "10"},
+		),
+		component.ApplyDataFunc(
+			func(r *http.Request) (interface{}, error) {
+				return fetchItems(r.Context(), component.PropsFromRequest(r).Number("max-items"))
+			},
+		),
+	)
+
+	// Inside the page.
+	&html.Component{Gear: gear, Attributes: []html.Attribute{html.Attr{Name: "user-name", Value: "John"}}}
+*/
+//
+// Lifecycle Hooks
+//
+/*
+OnConnected(), OnDisconnected() and OnAdopted() add Javascript that runs when the component enters, leaves or
+is moved between documents. In WASM builds, OnConnectedWasm(), OnDisconnectedWasm() and OnAdoptedWasm() call
+an html.WasmFunc instead.
+
+	gear, err := component.New(
+		"my-clock",
+		doc,
+		component.OnConnected(`this.timer = setInterval(() => this.update(), 1000);`),
+		component.OnDisconnected(`clearInterval(this.timer);`),
+	)
+*/
+//
 // Serving a page
 //
 // Now we need to serve the page and any external file required such as images or css files.
@@ -89,18 +207,10 @@ If you don't know about web components, a good introduction can be found here: h
 	// Attach our page containing the gear to "/".
 	h.MustHandle("/", doc)
 
-	// Serve the content using the http.Server.
-	server := &http.Server{
-		Addr:           fmt.Sprintf(":%d", *port),
-		Handler:        h.ServerMux(),
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+	// Serve the content until an interrupt or SIGTERM is received. See Serve() for TLS and other options.
+	if err := handlers.Serve(context.Background(), h, handlers.Addr(fmt.Sprintf(":%d", *port))); err != nil {
+		log.Fatal(err)
 	}
-
-	log.Printf("http server serving on :%d", *port)
-
-	log.Fatal(server.ListenAndServe())
 */
 package component
 
@@ -109,7 +219,9 @@ import (
 	"context"
 	"fmt"
 	"html/template"
+	"log"
 	"net/http"
+	"reflect"
 	"strings"
 	"sync"
 
@@ -119,28 +231,241 @@ import (
 var htmlTemplateTxt = `
 {{ define "template" }}
 <template id="{{.Self.Name}}Template">
-	{{.Self.Doc.ExecuteAsGear .}}
+	{{.Self.ExecuteContent .}}
+</template>
+{{ end }}
+`
+
+var shadowTemplateTxt = `
+{{ define "shadow" }}
+<template shadowrootmode="{{.Self.ShadowMode}}"{{if .Self.DelegatesFocus}} shadowrootdelegatesfocus{{end}}>
+	{{- range .Self.Sheets}}
+	<style>{{.CSS}}</style>
+	{{- end}}
+	{{.Self.ExecuteContent .}}
 </template>
 {{ end }}
 `
 
+var sheetTemplateTxt = `
+{{ define "sheet" }}
+<script>
+	window.webgearSheets = window.webgearSheets || {};
+	(() => {
+		let entry = {css: {{.CSSText}}, sheet: null};
+		if ('adoptedStyleSheets' in Document.prototype && 'replaceSync' in CSSStyleSheet.prototype) {
+			entry.sheet = new CSSStyleSheet();
+			entry.sheet.replaceSync(entry.css);
+		}
+		window.webgearSheets[{{.Name}}] = entry;
+	})();
+</script>
+{{ end }}
+`
+
+var lazyTemplateTxt = `
+{{ define "lazy" }}
+<script>
+	(() => {
+		const name = '{{.Self.Name}}';
+		const events = ['pointerdown', 'focusin', 'keydown'];
+		let loaded = false;
+		let observer = null;
+
+		function load() {
+			if (loaded) {
+				return;
+			}
+			loaded = true;
+			if (observer !== null) {
+				observer.disconnect();
+			}
+			document.querySelectorAll(name).forEach(el => {
+				events.forEach(e => el.removeEventListener(e, load));
+			});
+
+			fetch('{{.Self.LazyURL}}').then(resp => {
+				if (!resp.ok) {
+					throw new Error('status ' + resp.status);
+				}
+				return resp.text();
+			}).then(text => {
+				// Scripts in a contextual fragment are run when it is inserted, unlike with innerHTML.
+				document.body.appendChild(document.createRange().createContextualFragment(text));
+			}).catch(err => {
+				loaded = false;
+				console.error('could not load component ' + name + ': ' + err);
+			});
+		}
+
+		function watch() {
+			if ('IntersectionObserver' in window) {
+				observer = new IntersectionObserver(entries => {
+					if (entries.some(e => e.isIntersecting)) {
+						load();
+					}
+				});
+			}
+			document.querySelectorAll(name).forEach(el => {
+				if (observer !== null) {
+					observer.observe(el);
+				}
+				events.forEach(e => el.addEventListener(e, load));
+			});
+		}
+
+		if (document.readyState === 'loading') {
+			document.addEventListener('DOMContentLoaded', watch);
+		} else {
+			watch();
+		}
+	})();
+</script>
+{{ end }}
+`
+
 var scriptTemplateTxt = `
 {{ define "script" }}
 <script>
+	{{- if .Self.Closed}}
+	var {{.Self.LoaderName}} = (() => {
+	// Closed shadow roots are only reachable through this map, which is private to the loader.
+	const roots = new WeakMap();
+	function retainRoot(el, root) {
+		roots.set(el, root);
+	}
+	{{- if .Self.ConnectRoots}}
+	let connected = false;
+	{{- end}}
+	return function({{if .Self.ConnectRoots}}connect{{end}}) {
+		{{- if .Self.ConnectRoots}}
+		// The WASM html package passes connect to get a lookup for the closed roots, so that it can reach
+		// the elements inside them. Only the first caller is given the lookup.
+		if (typeof connect === 'function') {
+			if (connected) {
+				return false;
+			}
+			connected = true;
+			connect(el => roots.get(el));
+			return true;
+		}
+		{{- end}}
+	{{- else}}
 	function {{.Self.LoaderName}}() {
+	{{- end}}
+		{{- if .Self.HasSheets}}
+		// Adds the shared stylesheets to a shadow root, or <style> elements if constructable stylesheets are not supported.
+		function adoptSheets(root) {
+			{{.Self.SheetNamesJS}}.forEach(name => {
+				let entry = window.webgearSheets[name];
+				if (entry.sheet !== null) {
+					root.adoptedStyleSheets = [...root.adoptedStyleSheets, entry.sheet];
+					return;
+				}
+				let style = document.createElement('style');
+				style.textContent = entry.css;
+				root.prepend(style);
+			});
+		}
+		{{- end}}
 		if (!window.customElements.get('{{.Self.Name}}')) {
 			window.customElements.define(
 				'{{.Self.Name}}',
 				class extends HTMLElement {
+					{{- if .Self.HasProps}}
+					static get observedAttributes() {
+						return {{.Self.PropsJS}}.map(p => p.name);
+					}
+					{{- end}}
 					constructor() {
 						super();
+						{{- if .Self.Closed}}
+						// A declarative shadow root was rendered by the server, so we don't need the template.
+						const internals = this.attachInternals();
+						if (internals.shadowRoot !== null) {
+							retainRoot(this, internals.shadowRoot);
+							return;
+						}
+						let template = document.getElementById('{{.Self.Name}}Template');
+						let templateContent = template.content;
+
+						const shadowRoot = this.attachShadow({{.Self.ShadowInit}});
+						shadowRoot.appendChild(templateContent.cloneNode(true));
+						retainRoot(this, shadowRoot);
+						{{- if .Self.HasSheets}}
+						adoptSheets(shadowRoot);
+						{{- end}}
+						{{- else}}
+						// A declarative shadow root was rendered by the server, so we don't need the template.
+						if (this.shadowRoot !== null) {
+							return;
+						}
 						let template = document.getElementById('{{.Self.Name}}Template');
 						let templateContent = template.content;
 
-						const shadowRoot = this.attachShadow({mode: 'open'}).appendChild(templateContent.cloneNode(true));
+						const shadowRoot = this.attachShadow({{.Self.ShadowInit}}).appendChild(templateContent.cloneNode(true));
+						{{- if .Self.HasSheets}}
+						adoptSheets(this.shadowRoot);
+						{{- end}}
+						{{- end}}
 					}
+
+					{{- if .Self.HasProps}}
+					attributeChangedCallback(name, oldValue, newValue) {
+						if (oldValue === newValue) {
+							return;
+						}
+						let prop = {{.Self.PropsJS}}.find(p => p.name === name);
+						let value = newValue === null ? prop.def : newValue;
+						let root = {{.Self.RootJS}};
+						if (root) {
+							root.querySelectorAll('[data-webgear-prop="' + name + '"]').forEach(e => {
+								e.textContent = value;
+							});
+						}
+						this.dispatchEvent(
+							new CustomEvent(
+								'webgear-prop-changed',
+								{detail: {name: name, oldValue: oldValue, newValue: newValue}, bubbles: true, composed: true}
+							)
+						);
+					}
+					{{- end}}
+					{{- range .Self.Hooks}}
+					{{.Callback}}() {
+						const root = {{$.Self.RootJS}};
+						{{- range .Snippets}}
+						(() => {
+							{{.}}
+						})();
+						{{- end}}
+					}
+					{{- end}}
 				}
 			);
+			{{- if .Self.HasProps}}
+			let cls = window.customElements.get('{{.Self.Name}}');
+			{{.Self.PropsJS}}.forEach(p => {
+				Object.defineProperty(cls.prototype, p.prop, {
+					get() {
+						let v = this.hasAttribute(p.name) ? this.getAttribute(p.name) : null;
+						switch (p.type) {
+						case 'number':
+							return Number(v === null ? p.def : v);
+						case 'boolean':
+							return v === null ? p.def === 'true' : v !== 'false';
+						}
+						return v === null ? p.def : v;
+					},
+					set(v) {
+						if (p.type === 'boolean') {
+							v = v ? 'true' : 'false';
+						}
+						this.setAttribute(p.name, String(v));
+					}
+				});
+			});
+			{{- end}}
 		}
 		let old = document.getElementById("{{.Self.Name}}");
 		if (old !== null) {
@@ -148,6 +473,9 @@ var scriptTemplateTxt = `
 			document.body.replaceChild(newcomp, old);
 		}
 	}
+	{{- if .Self.Closed}}
+	})();
+	{{- end}}
 	{{.Self.LoaderName}}();
 </script>
 {{ end }}
@@ -167,6 +495,9 @@ var gearTmpl *template.Template
 
 func init() {
 	gearTmpl = template.Must(template.New("htmlTemplate").Parse(htmlTemplateTxt))
+	gearTmpl = template.Must(gearTmpl.New("shadowTemplate").Parse(shadowTemplateTxt))
+	gearTmpl = template.Must(gearTmpl.New("sheetTemplate").Parse(sheetTemplateTxt))
+	gearTmpl = template.Must(gearTmpl.New("lazyTemplate").Parse(lazyTemplateTxt))
 	gearTmpl = template.Must(gearTmpl.New("scriptTemplate").Parse(scriptTemplateTxt))
 	gearTmpl = template.Must(gearTmpl.New("combinedTxt").Parse(combinedTxt))
 	gearTmpl = template.Must(gearTmpl.New("justTemplate").Parse(justTemplate))
@@ -174,6 +505,11 @@ func init() {
 
 // DataFunc represents a function that provides data in the html.Pipeline.GearData. The DataFunc should
 // return data that will be stored in the html.Pipeline.GearData field. The returned object must be thread-safe.
+// If an error is returned, it is passed to html.Pipeline.Error() and the page fails to render. If the Gear
+// has a Fallback(), the Fallback is rendered instead unless the error was wrapped with Fatal().
+// The DataFuncs of all Gears on a page are started in parallel when the page begins rendering, with a limit
+// on how many run at once. The DataFunc is called once per page, unless the Gear has Props() and an
+// html.Component sets them with DeclarativeShadow(), in which case it is also called for that instance.
 type DataFunc func(r *http.Request) (interface{}, error)
 
 // Gear is a shadow-dom component.
@@ -187,6 +523,18 @@ type Gear struct {
 	name       string
 	loaderName string
 
+	declarative    bool
+	closed         bool
+	delegatesFocus bool
+	slotAssignment SlotAssignment
+	lazy           bool
+	fallback       *html.Doc
+	props          []Prop
+	adopted        []*StyleSheet
+	ownSheets      []*StyleSheet
+	// hooks are Javascript snippets keyed by the custom element lifecycle callback that runs them.
+	hooks map[string][]template.JS
+
 	wasmUpdateMu sync.Mutex
 	wasmUpdate   bool
 }
@@ -206,12 +554,27 @@ func ApplyDataFunc(f DataFunc) Option {
 
 // AddGear adds another Gear that will be called before this gear is called.  This allows a componenet to use
 // other components. You still must use html.Component{} to insert your custom tag where you want the componenet to be displayed.
+// A Gear added to more than one parent, or also added to the page, is only output once per page.
 func AddGear(newGear *Gear) Option {
+	if newGear == nil {
+		panic("AddGear() cannot be passed a nil *Gear")
+	}
 	return func(g *Gear) {
 		g.Gears = append(g.Gears, newGear)
 	}
 }
 
+// DeclarativeShadow causes every html.Component that uses this Gear to render the Gear's shadow DOM inside the
+// component's tag using a declarative shadow root (<template shadowrootmode="open">). This allows the component
+// to display without Javascript and prevents a flash of unstyled content when the page loads. The Gear still
+// must be added to the page, as the template and loader script are used as a fallback for browsers that do not
+// support declarative shadow roots and for components created by Javascript.
+func DeclarativeShadow() Option {
+	return func(g *Gear) {
+		g.declarative = true
+	}
+}
+
 // New creates a new Gear object called "name" using the HTML provided by the doc passed. Name also is used for the tag's
 // ID when using the html.Component{} type.
 func New(name string, doc *html.Doc, options ...Option) (*Gear, error) {
@@ -219,6 +582,16 @@ func New(name string, doc *html.Doc, options ...Option) (*Gear, error) {
 		return nil, err
 	}
 
+	switch {
+	case doc == nil:
+		return nil, fmt.Errorf("WebGear Component(%s) cannot have a nil *html.Doc", name)
+	case !emptyHead(doc.Head):
+		return nil, fmt.Errorf("WebGear Component(%s) has a Doc with a Head, which is never rendered inside a component. "+
+			"Move elements such as html.Link or html.Style into the Body", name)
+	case doc.Body == nil:
+		return nil, fmt.Errorf("WebGear Component(%s) has a Doc without a Body", name)
+	}
+
 	doc.Component = true
 	doc.Pretty = false // Inside a Gear, this should always be false.
 
@@ -227,9 +600,9 @@ func New(name string, doc *html.Doc, options ...Option) (*Gear, error) {
 	}
 
 	walkCtx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 	for walked := range html.Walker(walkCtx, doc.Body) {
 		if g, ok := walked.Element.(*Gear); ok {
-			cancel()
 			return nil, fmt.Errorf("WebGear Component(%s) had another component(%s) added directly to the passed *html.Doc,"+
 				"this can only be added using the component.AddGear() option to allow correct rendered ordering", name, g.name)
 		}
@@ -245,9 +618,39 @@ func New(name string, doc *html.Doc, options ...Option) (*Gear, error) {
 		o(g)
 	}
 
+	seen := map[string]bool{}
+	for _, p := range g.props {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("WebGear Component(%s): %w", name, err)
+		}
+		if seen[p.Name] {
+			return nil, fmt.Errorf("WebGear Component(%s): Prop(%s) was declared more than once", name, p.Name)
+		}
+		seen[p.Name] = true
+	}
+
+	if g.fallback != nil {
+		if err := g.fallback.Init(); err != nil {
+			return nil, fmt.Errorf("WebGear Component(%s) Fallback: %w", name, err)
+		}
+	}
+
+	if err := checkTree(g, map[string]*Gear{}, nil); err != nil {
+		return nil, fmt.Errorf("WebGear Component(%s): %w", name, err)
+	}
+
 	return g, nil
 }
 
+// emptyHead returns true if h is nil or has no Elements, attributes or Events. builder.NewHTML() requires
+// a Head, so an empty one is allowed.
+func emptyHead(h *html.Head) bool {
+	if h == nil {
+		return true
+	}
+	return len(h.Elements) == 0 && h.Events == nil && reflect.ValueOf(h.GlobalAttrs).IsZero()
+}
+
 // Name returns the name of the Gear so that it may be referenced.
 func (g *Gear) Name() string {
 	return g.name
@@ -276,31 +679,131 @@ func (g *Gear) TemplateName() string {
 }
 
 // Execute executes the internal templates and renders the html for output with the given pipeline.
+// If the Gear has already been output by this pipeline, such as when it is added to a page
+// and to another Gear with AddGear(), nothing is output.
 func (g *Gear) Execute(pipe html.Pipeline) string {
+	err := g.render(
+		pipe,
+		true,
+		func(sheet *StyleSheet) error {
+			return gearTmpl.ExecuteTemplate(pipe.W, "sheet", sheet)
+		},
+		func(pipe html.Pipeline) error {
+			return gearTmpl.ExecuteTemplate(pipe.W, "combinedTxt", pipe)
+		},
+	)
+	if err != nil {
+		pipe.Error(err)
+	}
+	return html.EmptyString
+}
+
+// render calls gearFn for g and every Gear added with AddGear() that has not already been output by pipe,
+// dependencies first. sheetFn is called before gearFn for each StyleSheet that has not already been output.
+// gearFn receives a Pipeline with .Self set to the Gear being rendered and .GearData set by its DataFunc.
+// If honorLazy is set, a Gear using Lazy() outputs the script that fetches it to pipe.W instead.
+func (g *Gear) render(pipe html.Pipeline, honorLazy bool, sheetFn func(sheet *StyleSheet) error, gearFn func(pipe html.Pipeline) error) error {
+	first, err := pipe.EmitOnce(g)
+	if err != nil {
+		return err
+	}
+	if !first {
+		return nil
+	}
+
+	if honorLazy && g.lazy {
+		pipe.Self = g
+		return gearTmpl.ExecuteTemplate(pipe.W, "lazy", pipe)
+	}
+
+	// Start all DataFuncs in the tree, as we only wait on each one when rendering its Gear. This does
+	// nothing for DataFuncs that html.Doc already started.
+	g.prefetch(pipe, honorLazy, map[*Gear]bool{})
+
+	for _, gear := range g.Gears {
+		if err := gear.render(pipe, honorLazy, sheetFn, gearFn); err != nil {
+			return err
+		}
+		if pipe.Ctx.Err() != nil {
+			return nil
+		}
+	}
+
+	for _, sheet := range g.Sheets() {
+		first, err := pipe.EmitKeyOnce("sheet:"+sheet.name, sheet)
+		if err != nil {
+			return fmt.Errorf("WebGear Component(%s): two different StyleSheets have the name %q", g.name, sheet.name)
+		}
+		if first {
+			if err := sheetFn(sheet); err != nil {
+				return err
+			}
+		}
+	}
+
+	pipe, err = g.pipeline(pipe, nil)
+	if err != nil {
+		return err
+	}
+	return gearFn(pipe)
+}
+
+// pipeline returns a copy of pipe for rendering the Gear with the props set by attrs. If the DataFunc fails
+// and the page cannot be rendered, an error is returned.
+func (g *Gear) pipeline(pipe html.Pipeline, attrs []html.Attribute) (html.Pipeline, error) {
+	pipe = g.withProps(pipe, attrs)
 	pipe.Self = g
 
 	if g.dataFunc != nil {
-		i, err := g.dataFunc(pipe.Req)
+		i, err := g.data(pipe, attrs)
 		if err != nil {
-			panic(err)
+			if err := g.dataErr(err); err != nil {
+				return pipe, err
+			}
+			i = failed{}
 		}
 		pipe.GearData = i
 	}
+	return pipe, nil
+}
 
-	var err error
-	for _, gear := range g.Gears {
-		gear.Execute(pipe)
-		if pipe.Ctx.Err() != nil {
-			return html.EmptyString
-		}
+// ExecuteShadow renders the Gear's shadow DOM as a declarative shadow root. This is called by html.Component
+// for each instance of the Gear and does nothing unless the DeclarativeShadow() option was used.
+func (g *Gear) ExecuteShadow(pipe html.Pipeline) string {
+	if !g.declarative {
+		return html.EmptyString
 	}
-
-	err = gearTmpl.ExecuteTemplate(pipe.W, "combinedTxt", pipe)
-	//err = gearTmpl.Execute(pipe.W, pipe)
+	var attrs []html.Attribute
+	if c, ok := pipe.Self.(*html.Component); ok {
+		attrs = c.Attributes
+	}
+	pipe, err := g.pipeline(pipe, attrs)
 	if err != nil {
-		panic(err)
+		pipe.Error(err)
+		return html.EmptyString
 	}
 
+	// The shadow root is written to a buffer so that the Fallback can replace it if rendering fails.
+	w := pipe.W
+	buff := &bytes.Buffer{}
+	pipe.W = buff
+	if err := gearTmpl.ExecuteTemplate(buff, "shadow", pipe); err != nil {
+		err = fmt.Errorf("WebGear Component(%s) shadow root: %w", g.name, err)
+		if g.fallback == nil {
+			pipe.Error(err)
+			return html.EmptyString
+		}
+		log.Printf("%s, rendering Fallback", err)
+		buff.Reset()
+		pipe.GearData = failed{}
+		if err := gearTmpl.ExecuteTemplate(buff, "shadow", pipe); err != nil {
+			pipe.Error(fmt.Errorf("WebGear Component(%s) Fallback: %w", g.name, err))
+			return html.EmptyString
+		}
+	}
+	if _, err := w.Write(buff.Bytes()); err != nil {
+		pipe.Error(fmt.Errorf("WebGear Component(%s) shadow root: %w", g.name, err))
+	}
 	return html.EmptyString
 }
 
@@ -318,6 +821,36 @@ func (g *Gear) TemplateContent() string {
 	return buff.String()
 }
 
+// checkTree checks g and all Gears added with AddGear() for cycles and for different Gears that share a name.
+// path is the names of the Gears that added g.
+func checkTree(g *Gear, names map[string]*Gear, path []string) error {
+	// A different Gear with a name in path is a name collision, not a cycle.
+	prev, ok := names[g.name]
+	if ok && prev != g {
+		return fmt.Errorf("two different Gears have the same name %q", g.name)
+	}
+	for _, p := range path {
+		if p == g.name {
+			return fmt.Errorf("AddGear() has a cycle: %s", strings.Join(append(path, g.name), " -> "))
+		}
+	}
+	if ok {
+		return nil
+	}
+	names[g.name] = g
+
+	path = append(path, g.name)
+	for _, child := range g.Gears {
+		if child == nil {
+			return fmt.Errorf("Gear(%s) has a nil Gear added", g.name)
+		}
+		if err := checkTree(child, names, path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func validName(s string) error {
 	hasHyphen := false
 	if len(s) == 0 {
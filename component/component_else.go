@@ -0,0 +1,7 @@
+// +build !js,!wasm
+
+package component
+
+// connectRoots causes loaders of Gears with closed shadow roots to hand the roots to the html package.
+// Pages rendered by a server never do, as nothing there needs them.
+const connectRoots = false
@@ -2,11 +2,17 @@ package component
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/johnsiilver/webgear/html"
+	"github.com/johnsiilver/webgear/html/builder"
 
 	"github.com/kylelemons/godebug/pretty"
 )
@@ -27,7 +33,7 @@ func TestComponent(t *testing.T) {
 					Elements: []html.Element{
 						&html.Div{
 							Elements: []html.Element{
-								&html.A{Href: "/self", Elements: []html.Element{html.TextElement("link")}},
+								&html.A{Href: html.URLParse("/self"), Elements: []html.Element{html.TextElement("link")}},
 							},
 						},
 					},
@@ -43,18 +49,31 @@ func TestComponent(t *testing.T) {
 </template>
 
 <script>
-	window.customElements.define(
-		'.Self.Name',
-		class extends HTMLElement {
-			constructor() {
-				super();
-				let template = document.getElementById('.Self.NameTemplate');
-				let templateContent = template.content;
-
-				const shadowRoot = this.attachShadow({mode: 'open'}).appendChild(templateContent.cloneNode(true));
-			}
+	function .Self.LoaderName() {
+		if (!window.customElements.get('.Self.Name')) {
+			window.customElements.define(
+				'.Self.Name',
+				class extends HTMLElement {
+					constructor() {
+						super();
+						if (this.shadowRoot !== null) {
+							return;
+						}
+						let template = document.getElementById('.Self.NameTemplate');
+						let templateContent = template.content;
+
+						const shadowRoot = this.attachShadow({mode: 'open'}).appendChild(templateContent.cloneNode(true));
+					}
+				}
+			);
+		}
+		let old = document.getElementById(".Self.Name");
+		if (old !== null) {
+			let newcomp = old.cloneNode(true);
+			document.body.replaceChild(newcomp, old);
 		}
-	);
+	}
+	.Self.LoaderName();
 </script>
 			`),
 		},
@@ -84,6 +103,7 @@ func TestComponent(t *testing.T) {
 		got := strings.TrimSpace(space.ReplaceAllString(string(build.String()), " "))
 		want := strings.TrimSpace(space.ReplaceAllString(string(test.want), " "))
 
+		want = strings.ReplaceAll(want, ".Self.LoaderName", string(g.LoaderName()))
 		want = strings.ReplaceAll(want, ".Self.Name", g.Name())
 
 		if diff := pretty.Compare(want, got); diff != "" {
@@ -91,3 +111,717 @@ func TestComponent(t *testing.T) {
 		}
 	}
 }
+
+func TestDeclarativeShadow(t *testing.T) {
+	tests := []struct {
+		desc    string
+		options []Option
+		want    string
+	}{
+		{
+			desc: "Without DeclarativeShadow()",
+			want: `<my-component id="my-component" > </my-component>`,
+		},
+		{
+			desc:    "With DeclarativeShadow()",
+			options: []Option{DeclarativeShadow()},
+			want:    `<my-component id="my-component" > <template shadowrootmode="open"> <div > hello </div> </template> </my-component>`,
+		},
+	}
+
+	for _, test := range tests {
+		doc := &html.Doc{
+			Body: &html.Body{
+				Elements: []html.Element{
+					&html.Div{Elements: []html.Element{html.TextElement("hello")}},
+				},
+			},
+		}
+		g, err := New("my-component", doc, test.options...)
+		if err != nil {
+			t.Fatalf("TestDeclarativeShadow(%s): got err == %s, want err == nil", test.desc, err)
+		}
+
+		build := &strings.Builder{}
+		pipe := html.NewPipeline(context.Background(), nil, build)
+		(&html.Component{Gear: g}).Execute(pipe)
+
+		space := regexp.MustCompile(`\s+`)
+		got := strings.TrimSpace(space.ReplaceAllString(build.String(), " "))
+
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("TestDeclarativeShadow(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+// failWriter fails any write that contains match.
+type failWriter struct {
+	strings.Builder
+	match string
+}
+
+func (f *failWriter) Write(b []byte) (int, error) {
+	if strings.Contains(string(b), f.match) {
+		return 0, fmt.Errorf("write failed")
+	}
+	return f.Builder.Write(b)
+}
+
+func TestDeclarativeShadowErrors(t *testing.T) {
+	doc := &html.Doc{Body: &html.Body{Elements: []html.Element{html.TextElement("hello")}}}
+	g, err := New("my-component", doc, DeclarativeShadow(), Fallback(html.TextElement("unavailable")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &failWriter{match: "shadowrootmode"}
+	pipe := html.NewPipeline(context.Background(), nil, w)
+	(&html.Component{Gear: g}).Execute(pipe)
+
+	if err := pipe.HadError(); err == nil {
+		t.Errorf("TestDeclarativeShadowErrors: got err == nil, want err != nil")
+	}
+}
+
+func TestSlots(t *testing.T) {
+	doc := &html.Doc{
+		Body: &html.Body{
+			Elements: []html.Element{
+				&html.Slot{Name: "title"},
+				&html.Slot{Elements: []html.Element{html.TextElement("empty")}},
+			},
+		},
+	}
+	g, err := New("my-card", doc, DeclarativeShadow())
+	if err != nil {
+		t.Fatalf("TestSlots: got err == %s, want err == nil", err)
+	}
+
+	comp := &html.Component{
+		Gear: g,
+		Elements: []html.Element{
+			&html.Span{GlobalAttrs: html.GlobalAttrs{Slot: "title"}, Elements: []html.Element{html.TextElement("Title")}},
+			html.TextElement("body"),
+		},
+	}
+
+	build := &strings.Builder{}
+	comp.Execute(html.NewPipeline(context.Background(), nil, build))
+
+	space := regexp.MustCompile(`\s+`)
+	got := strings.TrimSpace(space.ReplaceAllString(build.String(), " "))
+
+	want := `<my-card id="my-card" > <template shadowrootmode="open"> <slot name="title" > </slot> <slot > empty </slot> </template> <span slot="title" > Title </span> body </my-card>`
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("TestSlots: -want/+got:\n%s", diff)
+	}
+}
+
+func TestProps(t *testing.T) {
+	tests := []struct {
+		desc      string
+		props     []Prop
+		attrs     []html.Attribute
+		want      string
+		newErr    bool
+		renderErr bool
+	}{
+		{
+			desc:   "Invalid prop name",
+			props:  []Prop{{Name: "Title"}},
+			newErr: true,
+		},
+		{
+			desc:   "Invalid default",
+			props:  []Prop{{Name: "count", Type: NumberProp, Default: "one"}},
+			newErr: true,
+		},
+		{
+			desc:      "Invalid attribute value",
+			props:     []Prop{{Name: "count", Type: NumberProp}},
+			attrs:     []html.Attribute{html.Attr{Name: "count", Value: "one"}},
+			renderErr: true,
+		},
+		{
+			desc:  "Defaults",
+			props: []Prop{{Name: "title", Default: "none"}, {Name: "count", Type: NumberProp, Default: "1"}},
+			want:  `<my-props id="my-props" > <template shadowrootmode="open"> <span data-webgear-prop="title">none</span> 1 </template> </my-props>`,
+		},
+		{
+			desc:  "Attributes",
+			props: []Prop{{Name: "title", Default: "none"}, {Name: "count", Type: NumberProp, Default: "1"}},
+			attrs: []html.Attribute{html.Attr{Name: "title", Value: "<b>"}, html.Attr{Name: "count", Value: "2"}},
+			want:  `<my-props id="my-props" title="&lt;b&gt;" count="2" > <template shadowrootmode="open"> <span data-webgear-prop="title">&lt;b&gt;</span> 2 </template> </my-props>`,
+		},
+	}
+
+	for _, test := range tests {
+		doc := &html.Doc{
+			Body: &html.Body{
+				Elements: []html.Element{
+					PropText("title"),
+					html.Dynamic(
+						func(pipe html.Pipeline) []html.Element {
+							return []html.Element{html.TextElement(pipe.GearData.(string))}
+						},
+					),
+				},
+			},
+		}
+		df := func(r *http.Request) (interface{}, error) {
+			return fmt.Sprint(PropsFromRequest(r).Number("count")), nil
+		}
+
+		g, err := New("my-props", doc, DeclarativeShadow(), Props(test.props...), ApplyDataFunc(df))
+		switch {
+		case err == nil && test.newErr:
+			t.Errorf("TestProps(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.newErr:
+			t.Errorf("TestProps(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+
+		build := &strings.Builder{}
+		pipe := html.NewPipeline(context.Background(), nil, build)
+		(&html.Component{Gear: g, Attributes: test.attrs}).Execute(pipe)
+		err = pipe.HadError()
+		switch {
+		case err == nil && test.renderErr:
+			t.Errorf("TestProps(%s): got render err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.renderErr:
+			t.Errorf("TestProps(%s): got render err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+
+		space := regexp.MustCompile(`\s+`)
+		got := strings.TrimSpace(space.ReplaceAllString(build.String(), " "))
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("TestProps(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestPropsDataFuncPanic(t *testing.T) {
+	df := func(r *http.Request) (interface{}, error) {
+		if PropsFromRequest(r).String("title") != "none" {
+			panic("bad title")
+		}
+		return nil, nil
+	}
+	doc := &html.Doc{Body: &html.Body{Elements: []html.Element{html.TextElement("doc-text")}}}
+	g, err := New(
+		"my-props",
+		doc,
+		DeclarativeShadow(),
+		Props(Prop{Name: "title", Default: "none"}),
+		ApplyDataFunc(df),
+		Fallback(html.TextElement("unavailable")),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The DataFunc is called for this instance because it sets a prop, which must be handled like a prefetch.
+	build := &strings.Builder{}
+	pipe := html.NewPipeline(context.Background(), nil, build)
+	(&html.Component{Gear: g, Attributes: []html.Attribute{html.Attr{Name: "title", Value: "other"}}}).Execute(pipe)
+
+	if err := pipe.HadError(); err != nil {
+		t.Fatalf("TestPropsDataFuncPanic: got err == %s, want err == nil", err)
+	}
+	if !strings.Contains(build.String(), "unavailable") {
+		t.Errorf("TestPropsDataFuncPanic: output did not contain the Fallback:\n%s", build.String())
+	}
+}
+
+func TestExecuteOnce(t *testing.T) {
+	newDoc := func() *html.Doc {
+		return &html.Doc{Body: &html.Body{Elements: []html.Element{html.TextElement("hello")}}}
+	}
+
+	child, err := New("my-child", newDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+	parentA, err := New("my-parent-a", newDoc(), AddGear(child))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parentB, err := New("my-parent-b", newDoc(), AddGear(child))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := &strings.Builder{}
+	pipe := html.NewPipeline(context.Background(), nil, build)
+	for _, g := range []*Gear{parentA, parentB, child, parentA} {
+		g.Execute(pipe)
+	}
+	if err := pipe.HadError(); err != nil {
+		t.Fatalf("TestExecuteOnce: got err == %s, want err == nil", err)
+	}
+
+	for _, g := range []*Gear{child, parentA, parentB} {
+		got := strings.Count(build.String(), `<template id="`+g.TemplateName()+`">`)
+		if got != 1 {
+			t.Errorf("TestExecuteOnce(%s): template was output %d times, want 1", g.Name(), got)
+		}
+	}
+
+	// A different Gear with a name that has already been output is an error.
+	dup, err := New("my-child", newDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dup.Execute(pipe)
+	if err := pipe.HadError(); err == nil {
+		t.Errorf("TestExecuteOnce(name collision): got err == nil, want err != nil")
+	}
+
+	// The collision is caught by New() when it is within the AddGear() tree.
+	if _, err := New("my-parent-c", newDoc(), AddGear(child), AddGear(dup)); err == nil {
+		t.Errorf("TestExecuteOnce(New() name collision): got err == nil, want err != nil")
+	}
+}
+
+func TestLifecycleHooks(t *testing.T) {
+	g, err := New(
+		"my-clock",
+		&html.Doc{Body: &html.Body{}},
+		OnConnected("this.timer = setInterval(() => {}, 1000);"),
+		OnConnected("console.log('connected');"),
+		OnDisconnected("clearInterval(this.timer);"),
+		OnAdopted("console.log('adopted');"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := &strings.Builder{}
+	g.Execute(html.NewPipeline(context.Background(), nil, build))
+
+	space := regexp.MustCompile(`\s+`)
+	got := space.ReplaceAllString(build.String(), " ")
+
+	want := []string{
+		"adoptedCallback() { const root = this.shadowRoot; (() => { console.log('adopted'); })(); }",
+		"connectedCallback() { const root = this.shadowRoot; (() => { this.timer = setInterval(() => {}, 1000); })(); (() => { console.log('connected'); })(); }",
+		"disconnectedCallback() { const root = this.shadowRoot; (() => { clearInterval(this.timer); })(); }",
+	}
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("TestLifecycleHooks: output did not contain %q:\n%s", w, got)
+		}
+	}
+}
+
+func TestShadowOptions(t *testing.T) {
+	g, err := New(
+		"my-widget",
+		&html.Doc{Body: &html.Body{}},
+		DeclarativeShadow(),
+		ClosedShadow(),
+		DelegatesFocus(),
+		AssignSlots(ManualSlots),
+		Props(Prop{Name: "title"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := &strings.Builder{}
+	pipe := html.NewPipeline(context.Background(), nil, build)
+	g.Execute(pipe)
+	(&html.Component{Gear: g}).Execute(pipe)
+
+	space := regexp.MustCompile(`\s+`)
+	got := space.ReplaceAllString(build.String(), " ")
+
+	want := []string{
+		"var mywidgetLoader = (() => {",
+		"const roots = new WeakMap();",
+		"if (internals.shadowRoot !== null) { retainRoot(this, internals.shadowRoot); return; }",
+		"this.attachShadow({mode: 'closed', delegatesFocus: true, slotAssignment: 'manual'});",
+		"let root = roots.get(this);",
+		`<template shadowrootmode="closed" shadowrootdelegatesfocus>`,
+	}
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("TestShadowOptions: output did not contain %q:\n%s", w, got)
+		}
+	}
+	// Roots must not be reachable from outside the loader when the page is not rendered by WASM.
+	for _, w := range []string{"webgearShadowRoots", "connect"} {
+		if strings.Contains(got, w) {
+			t.Errorf("TestShadowOptions: output contained %q:\n%s", w, got)
+		}
+	}
+}
+
+func TestStyleSheets(t *testing.T) {
+	tokens, err := NewStyleSheet("tokens", ":host { --primary: blue; }")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewStyleSheet("bad:name", ""); err == nil {
+		t.Errorf("TestStyleSheets(NewStyleSheet with bad name): got err == nil, want err != nil")
+	}
+
+	newDoc := func() *html.Doc {
+		return &html.Doc{Body: &html.Body{}}
+	}
+	a, err := New("my-a", newDoc(), AdoptStyleSheets(tokens), SharedStyle("p { color: var(--primary); }"), DeclarativeShadow())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := New("my-b", newDoc(), AdoptStyleSheets(tokens))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := &strings.Builder{}
+	pipe := html.NewPipeline(context.Background(), nil, build)
+	a.Execute(pipe)
+	b.Execute(pipe)
+	(&html.Component{Gear: a}).Execute(pipe)
+	if err := pipe.HadError(); err != nil {
+		t.Fatalf("TestStyleSheets: got err == %s, want err == nil", err)
+	}
+
+	space := regexp.MustCompile(`\s+`)
+	got := space.ReplaceAllString(build.String(), " ")
+
+	if n := strings.Count(got, `window.webgearSheets["tokens"] = entry;`); n != 1 {
+		t.Errorf("TestStyleSheets: tokens StyleSheet was output %d times, want 1", n)
+	}
+	want := []string{
+		`window.webgearSheets["gear:my-a:0"] = entry;`,
+		`["tokens","gear:my-a:0"].forEach(name =>`,
+		`adoptSheets(this.shadowRoot);`,
+		`<template shadowrootmode="open"> <style>:host { --primary: blue; }</style> <style>p { color: var(--primary); }</style>`,
+	}
+	for _, w := range want {
+		if !strings.Contains(got, w) {
+			t.Errorf("TestStyleSheets: output did not contain %q:\n%s", w, got)
+		}
+	}
+}
+
+func TestBundle(t *testing.T) {
+	newDoc := func(text string) *html.Doc {
+		return &html.Doc{Body: &html.Body{Elements: []html.Element{html.TextElement(text)}}}
+	}
+
+	child, err := New("my-child", newDoc("child"), SharedStyle("p { color: red; }"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := New("my-parent", newDoc("parent"), AddGear(child))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buff := &strings.Builder{}
+	if err := Bundle(context.Background(), parent, buff); err != nil {
+		t.Fatalf("TestBundle: got err == %s, want err == nil", err)
+	}
+	got := buff.String()
+
+	if strings.Contains(got, "<script>") {
+		t.Errorf("TestBundle: bundle contained a <script> tag:\n%s", got)
+	}
+
+	// Order matters: the child's stylesheet, then the child, then the parent.
+	want := []string{
+		`\u003ctemplate id=\"my-childTemplate\"\u003e`,
+		`\u003ctemplate id=\"my-parentTemplate\"\u003e`,
+		`window.webgearSheets["gear:my-child:0"] = entry;`,
+		"mychildLoader();",
+		"myparentLoader();",
+	}
+	last := -1
+	for _, w := range want {
+		i := strings.Index(got, w)
+		if i == -1 {
+			t.Errorf("TestBundle: bundle did not contain %q:\n%s", w, got)
+			continue
+		}
+		if i < last {
+			t.Errorf("TestBundle: %q was out of order:\n%s", w, got)
+		}
+		last = i
+	}
+}
+
+func TestLazy(t *testing.T) {
+	newDoc := func(text string) *html.Doc {
+		return &html.Doc{Body: &html.Body{Elements: []html.Element{html.TextElement(text)}}}
+	}
+
+	child, err := New("my-child", newDoc("child"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lazy, err := New("my-lazy", newDoc("lazy"), AddGear(child), Lazy())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	build := &strings.Builder{}
+	lazy.Execute(html.NewPipeline(context.Background(), nil, build))
+	got := build.String()
+
+	// html/template escapes "/" inside Javascript strings.
+	if !strings.Contains(got, `fetch('\/webgear\/gear\/my-lazy')`) {
+		t.Errorf("TestLazy: stub did not fetch the Gear:\n%s", got)
+	}
+	if strings.Contains(got, "<template") {
+		t.Errorf("TestLazy: stub contained a <template>:\n%s", got)
+	}
+
+	pattern, h := lazy.LazyHandler()
+	if pattern != "/webgear/gear/my-lazy" {
+		t.Errorf("TestLazy: LazyHandler() pattern: got %q, want %q", pattern, "/webgear/gear/my-lazy")
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, pattern, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("TestLazy: LazyHandler() status: got %d, want %d", w.Code, http.StatusOK)
+	}
+	for _, tmpl := range []string{`<template id="my-childTemplate">`, `<template id="my-lazyTemplate">`} {
+		if !strings.Contains(w.Body.String(), tmpl) {
+			t.Errorf("TestLazy: LazyHandler() output did not contain %q:\n%s", tmpl, w.Body.String())
+		}
+	}
+
+	if pattern, _ := child.LazyHandler(); pattern != "" {
+		t.Errorf("TestLazy: LazyHandler() on Gear without Lazy(): got pattern %q, want empty", pattern)
+	}
+}
+
+func TestDataFuncErrors(t *testing.T) {
+	tests := []struct {
+		desc     string
+		err      error
+		fallback bool
+		want     string
+		notWant  string
+		wantErr  bool
+	}{
+		{desc: "Success", want: "doc-text"},
+		{desc: "Error without Fallback", err: fmt.Errorf("error"), wantErr: true},
+		{desc: "Error with Fallback", err: fmt.Errorf("error"), fallback: true, want: "unavailable", notWant: "doc-text"},
+		{desc: "Fatal error with Fallback", err: Fatal(fmt.Errorf("error")), fallback: true, wantErr: true},
+	}
+
+	for _, test := range tests {
+		doc := &html.Doc{Body: &html.Body{Elements: []html.Element{html.TextElement("doc-text")}}}
+		df := func(r *http.Request) (interface{}, error) {
+			return nil, test.err
+		}
+		options := []Option{ApplyDataFunc(df), DeclarativeShadow()}
+		if test.fallback {
+			options = append(options, Fallback(html.TextElement("unavailable")))
+		}
+
+		g, err := New("my-data", doc, options...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, e := range []html.Element{g, &html.Component{Gear: g}} {
+			build := &strings.Builder{}
+			pipe := html.NewPipeline(context.Background(), nil, build)
+			e.Execute(pipe)
+
+			err = pipe.HadError()
+			switch {
+			case err == nil && test.wantErr:
+				t.Errorf("TestDataFuncErrors(%s)(%T): got err == nil, want err != nil", test.desc, e)
+				continue
+			case err != nil && !test.wantErr:
+				t.Errorf("TestDataFuncErrors(%s)(%T): got err == %s, want err == nil", test.desc, e, err)
+				continue
+			case err != nil:
+				continue
+			}
+
+			if !strings.Contains(build.String(), test.want) {
+				t.Errorf("TestDataFuncErrors(%s)(%T): output did not contain %q:\n%s", test.desc, e, test.want, build.String())
+			}
+			if test.notWant != "" && strings.Contains(build.String(), test.notWant) {
+				t.Errorf("TestDataFuncErrors(%s)(%T): output contained %q:\n%s", test.desc, e, test.notWant, build.String())
+			}
+		}
+	}
+}
+
+func TestPrefetch(t *testing.T) {
+	// Each DataFunc waits until all of them have started, which only happens if they run in parallel.
+	started := sync.WaitGroup{}
+	started.Add(3)
+	df := func(r *http.Request) (interface{}, error) {
+		started.Done()
+
+		done := make(chan struct{})
+		go func() {
+			started.Wait()
+			close(done)
+		}()
+		select {
+		case <-done:
+			return "gear-data", nil
+		case <-time.After(5 * time.Second):
+			return nil, fmt.Errorf("DataFuncs did not run in parallel")
+		}
+	}
+
+	newDoc := func() *html.Doc {
+		return &html.Doc{
+			Body: &html.Body{
+				Elements: []html.Element{
+					html.Dynamic(
+						func(pipe html.Pipeline) []html.Element {
+							return []html.Element{html.TextElement(pipe.GearData.(string))}
+						},
+					),
+				},
+			},
+		}
+	}
+
+	child, err := New("my-child", newDoc(), ApplyDataFunc(df))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parent, err := New("my-parent", newDoc(), ApplyDataFunc(df), AddGear(child))
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := New("my-other", newDoc(), ApplyDataFunc(df))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := &html.Doc{
+		Head: &html.Head{Elements: []html.Element{&html.Title{TagValue: html.TextElement("title")}}},
+		Body: &html.Body{Elements: []html.Element{parent, other}},
+	}
+	if err := doc.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	build := &strings.Builder{}
+	if err := doc.Execute(context.Background(), build, nil); err != nil {
+		t.Fatalf("TestPrefetch: got err == %s, want err == nil", err)
+	}
+	if got := strings.Count(build.String(), "gear-data"); got != 3 {
+		t.Errorf("TestPrefetch: got GearData in %d Gears, want 3", got)
+	}
+}
+
+func TestPrefetchCanceled(t *testing.T) {
+	ctxs := make(chan context.Context, 1)
+	df := func(r *http.Request) (interface{}, error) {
+		ctxs <- r.Context()
+		return "gear-data", nil
+	}
+
+	g, err := New("my-gear", &html.Doc{Body: &html.Body{}}, ApplyDataFunc(df))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc := &html.Doc{
+		Head: &html.Head{Elements: []html.Element{&html.Title{TagValue: html.TextElement("title")}}},
+		Body: &html.Body{Elements: []html.Element{g}},
+	}
+	if err := doc.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := doc.Execute(context.Background(), &strings.Builder{}, httptest.NewRequest("GET", "/", nil)); err != nil {
+		t.Fatalf("TestPrefetchCanceled: got err == %s, want err == nil", err)
+	}
+	// DataFuncs that are still running after the page is written must be able to stop.
+	if err := (<-ctxs).Err(); err == nil {
+		t.Errorf("TestPrefetchCanceled: the DataFunc's request context was not canceled after Execute() returned")
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	newDoc := func() *html.Doc {
+		return &html.Doc{Body: &html.Body{Elements: []html.Element{html.TextElement("hello")}}}
+	}
+
+	a, err := New("gear-a", newDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := New("gear-b", newDoc(), AddGear(a))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Create a cycle, which cannot be done with options alone.
+	a.Gears = append(a.Gears, b)
+
+	tests := []struct {
+		desc    string
+		doc     *html.Doc
+		options []Option
+	}{
+		{desc: "nil Doc"},
+		{
+			desc: "Doc has a Head",
+			doc: &html.Doc{
+				Head: &html.Head{Elements: []html.Element{&html.Title{TagValue: html.TextElement("title")}}},
+				Body: &html.Body{},
+			},
+		},
+		{
+			desc: "Doc has a Head with attributes",
+			doc: &html.Doc{
+				Head: &html.Head{GlobalAttrs: html.GlobalAttrs{ID: "head"}},
+				Body: &html.Body{},
+			},
+		},
+		{desc: "Doc has no Body", doc: &html.Doc{}},
+		{desc: "Cycle", doc: newDoc(), options: []Option{AddGear(b)}},
+	}
+
+	for _, test := range tests {
+		if _, err := New("my-gear", test.doc, test.options...); err == nil {
+			t.Errorf("TestNewErrors(%s): got err == nil, want err != nil", test.desc)
+		}
+	}
+
+	// A different Gear named like a Gear that added it is a name collision, not a cycle.
+	other, err := New("my-gear", newDoc())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := New("gear-c", newDoc(), AddGear(other))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = New("my-gear", newDoc(), AddGear(c))
+	if err == nil || !strings.Contains(err.Error(), "same name") {
+		t.Errorf("TestNewErrors(name collision in path): got err == %v, want a name collision", err)
+	}
+}
+
+func TestNewWithBuilder(t *testing.T) {
+	b := builder.NewHTML(&html.Head{}, &html.Body{})
+	b.Into(&html.Div{})
+	b.Add(html.TextElement("hello"))
+
+	if _, err := New("built-gear", b.Doc()); err != nil {
+		t.Errorf("TestNewWithBuilder: got err == %s, want err == nil", err)
+	}
+}
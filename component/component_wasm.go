@@ -3,9 +3,58 @@
 package component
 
 import (
+	"fmt"
+	"html/template"
 	"syscall/js"
+
+	"github.com/johnsiilver/webgear/html"
 )
 
+// connectRoots causes loaders of Gears with closed shadow roots to hand the roots to the html package.
+const connectRoots = true
+
+// OnConnectedWasm is like OnConnected() except the Go function fn is called. "this" is the custom element,
+// root is its shadow root and args is passed through.
+func OnConnectedWasm(fn html.WasmFunc, args interface{}) Option {
+	return func(g *Gear) {
+		g.addWasmHook(connectedCallback, fn, args)
+	}
+}
+
+// OnDisconnectedWasm is like OnDisconnected() except the Go function fn is called.
+func OnDisconnectedWasm(fn html.WasmFunc, args interface{}) Option {
+	return func(g *Gear) {
+		g.addWasmHook(disconnectedCallback, fn, args)
+	}
+}
+
+// OnAdoptedWasm is like OnAdopted() except the Go function fn is called.
+func OnAdoptedWasm(fn html.WasmFunc, args interface{}) Option {
+	return func(g *Gear) {
+		g.addWasmHook(adoptedCallback, fn, args)
+	}
+}
+
+// addWasmHook registers fn as a global Javascript function and adds a hook that calls it. The function
+// is never released, as the callback can be called any number of times.
+func (g *Gear) addWasmHook(callback string, fn html.WasmFunc, args interface{}) {
+	if fn == nil {
+		panic("a lifecycle hook cannot have a nil WasmFunc")
+	}
+
+	name := fmt.Sprintf("webgear_%s_%s%d", g.loaderName, callback, len(g.hooks[callback]))
+	js.Global().Set(
+		name,
+		js.FuncOf(
+			func(this js.Value, jsArgs []js.Value) interface{} {
+				go fn(jsArgs[0], jsArgs[1], args)
+				return nil
+			},
+		),
+	)
+	g.addHook(callback, template.JS(fmt.Sprintf("window.%s(this, root);", name)))
+}
+
 // UpdateDOM updates the DOM for this component.
 func (g *Gear) UpdateDOM() error {
 	js.Global().Get("document").Call("getElementById", g.TemplateName()).Set("outerHTML", g.TemplateContent())
@@ -0,0 +1,250 @@
+/*
+Package componenttest provides snapshot testing for Gears. A Gear is rendered the way it is on a page, including
+its template, loader and the custom element that displays it. The output is normalized so that attribute order
+and whitespace do not cause differences and compared to a golden file in the testdata/ directory.
+
+Usage:
+
+	func TestNav(t *testing.T) {
+		conf := &config.VideoFiles{
+			&config.VideoFile{Index: 0, Name: "Grand Tetons", URL: "https://vimeo.com/19777306"},
+		}
+
+		gear, err := nav.New("nav-component", conf, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		componenttest.Golden(t, "nav-one-video", gear)
+	}
+
+Run the tests with -update to write the golden files after an intended change:
+
+	go test ./nav/ -update
+*/
+package componenttest
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/johnsiilver/webgear/component"
+	"github.com/johnsiilver/webgear/html"
+	"github.com/kylelemons/godebug/diff"
+	xhtml "golang.org/x/net/html"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/")
+
+type options struct {
+	req   *http.Request
+	attrs []html.Attribute
+}
+
+// Option is an optional argument to Render() and Golden().
+type Option func(o *options)
+
+// Request sets the *http.Request that the Gear's DataFunc and any html.Dynamic elements receive.
+// By default this is a GET request for "/".
+func Request(r *http.Request) Option {
+	return func(o *options) {
+		o.req = r
+	}
+}
+
+// Attributes sets attributes on the custom element that displays the Gear, such as the Gear's props.
+func Attributes(attrs ...html.Attribute) Option {
+	return func(o *options) {
+		o.attrs = append(o.attrs, attrs...)
+	}
+}
+
+// Render renders the Gear on a page by itself and returns the normalized content of the page's body.
+func Render(g *component.Gear, opts ...Option) (string, error) {
+	if g == nil {
+		return "", fmt.Errorf("Render() cannot be passed a nil *Gear")
+	}
+
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.req == nil {
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		if err != nil {
+			return "", err
+		}
+		o.req = req
+	}
+
+	doc := &html.Doc{
+		Head: &html.Head{},
+		Body: &html.Body{
+			Elements: []html.Element{
+				g,
+				&html.Component{Gear: g, Attributes: o.attrs},
+			},
+		},
+	}
+	if err := doc.Init(); err != nil {
+		return "", err
+	}
+
+	buff := &bytes.Buffer{}
+	if err := doc.Execute(context.Background(), buff, o.req); err != nil {
+		return "", err
+	}
+	return normalizeBody(buff)
+}
+
+// Normalize returns the HTML with one tag or piece of text per line, indented by its depth. Attributes are
+// sorted by name, whitespace in text is collapsed and blank lines in <script> and <style> are removed.
+func Normalize(s string) (string, error) {
+	return normalize(strings.NewReader(s), false)
+}
+
+// normalizeBody normalizes only the content of the <body>.
+func normalizeBody(r io.Reader) (string, error) {
+	return normalize(r, true)
+}
+
+// voidElements are elements that never have an end tag.
+var voidElements = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "source": true, "track": true, "wbr": true,
+}
+
+// rawElements are elements whose content is not HTML.
+var rawElements = map[string]bool{"script": true, "style": true, "textarea": true, "pre": true}
+
+func normalize(r io.Reader, bodyOnly bool) (string, error) {
+	out := &strings.Builder{}
+	depth := 0
+	raw := ""
+	inBody := !bodyOnly
+
+	line := func(s string) {
+		if !inBody {
+			return
+		}
+		out.WriteString(strings.Repeat("  ", depth))
+		out.WriteString(s)
+		out.WriteString("\n")
+	}
+
+	z := xhtml.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case xhtml.ErrorToken:
+			if z.Err() == io.EOF {
+				return out.String(), nil
+			}
+			return "", z.Err()
+		case xhtml.DoctypeToken:
+			line(z.Token().String())
+		case xhtml.CommentToken:
+			line(z.Token().String())
+		case xhtml.StartTagToken, xhtml.SelfClosingTagToken:
+			tok := z.Token()
+			if bodyOnly && tok.Data == "body" {
+				inBody, depth = true, 0
+				continue
+			}
+			line(startTag(tok))
+			if voidElements[tok.Data] || tok.Type == xhtml.SelfClosingTagToken {
+				continue
+			}
+			depth++
+			if rawElements[tok.Data] {
+				raw = tok.Data
+			}
+		case xhtml.EndTagToken:
+			tok := z.Token()
+			if bodyOnly && tok.Data == "body" {
+				inBody = false
+				continue
+			}
+			if voidElements[tok.Data] {
+				continue
+			}
+			if depth > 0 {
+				depth--
+			}
+			raw = ""
+			line("</" + tok.Data + ">")
+		case xhtml.TextToken:
+			text := string(z.Text())
+			if raw != "" {
+				for _, l := range strings.Split(text, "\n") {
+					if l = strings.TrimSpace(l); l != "" {
+						line(l)
+					}
+				}
+				continue
+			}
+			if text = strings.Join(strings.Fields(text), " "); text != "" {
+				line(xhtml.EscapeString(text))
+			}
+		}
+	}
+}
+
+// startTag outputs the start tag with the attributes sorted by name.
+func startTag(tok xhtml.Token) string {
+	attrs := make([]xhtml.Attribute, len(tok.Attr))
+	copy(attrs, tok.Attr)
+	sort.SliceStable(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+
+	b := &strings.Builder{}
+	b.WriteString("<" + tok.Data)
+	for _, a := range attrs {
+		b.WriteString(" " + a.Key)
+		if a.Val != "" {
+			b.WriteString(`="` + xhtml.EscapeString(a.Val) + `"`)
+		}
+	}
+	if tok.Type == xhtml.SelfClosingTagToken {
+		b.WriteString("/")
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// Golden renders the Gear with Render() and compares the output to testdata/<name>.golden. If the test is run
+// with -update, the golden file is written instead.
+func Golden(t testing.TB, name string, g *component.Gear, opts ...Option) {
+	t.Helper()
+
+	got, err := Render(g, opts...)
+	if err != nil {
+		t.Fatalf("Golden(%s): %s", name, err)
+	}
+
+	p := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatalf("Golden(%s): %s", name, err)
+		}
+		if err := os.WriteFile(p, []byte(got), 0644); err != nil {
+			t.Fatalf("Golden(%s): %s", name, err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatalf("Golden(%s): %s (run the test with -update to create it)", name, err)
+	}
+	if got != string(want) {
+		t.Errorf("Golden(%s): output does not match %s (-want +got):\n%s", name, p, diff.Diff(string(want), got))
+	}
+}
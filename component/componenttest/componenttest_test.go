@@ -0,0 +1,78 @@
+package componenttest
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/johnsiilver/webgear/component"
+	"github.com/johnsiilver/webgear/html"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		desc string
+		html string
+		want string
+	}{
+		{
+			desc: "Attribute order and whitespace",
+			html: "<div  id=\"a\" class=\"b\">\n\t  hello\n   world <br>\n</div>",
+			want: "<div class=\"b\" id=\"a\">\n  hello world\n  <br>\n</div>\n",
+		},
+		{
+			desc: "Script keeps its lines",
+			html: "<script>\n\n  const a = 1;\n\n    const b = 2;\n</script>",
+			want: "<script>\n  const a = 1;\n  const b = 2;\n</script>\n",
+		},
+	}
+
+	for _, test := range tests {
+		got, err := Normalize(test.html)
+		if err != nil {
+			t.Errorf("TestNormalize(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("TestNormalize(%s): got\n%q\nwant\n%q", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestGolden(t *testing.T) {
+	doc := &html.Doc{
+		Body: &html.Body{
+			Elements: []html.Element{
+				&html.Div{
+					GlobalAttrs: html.GlobalAttrs{Class: "greeting"},
+					Elements: []html.Element{
+						html.Dynamic(
+							func(pipe html.Pipeline) []html.Element {
+								return []html.Element{html.TextElement(pipe.GearData.(string))}
+							},
+						),
+					},
+				},
+			},
+		},
+	}
+
+	gear, err := component.New(
+		"hello-world",
+		doc,
+		component.ApplyDataFunc(
+			func(r *http.Request) (interface{}, error) {
+				return "Hello, " + r.URL.Query().Get("name"), nil
+			},
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "/?name=world", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Golden(t, "hello-world", gear, Request(req))
+}
@@ -0,0 +1,71 @@
+package component
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// Fallback is rendered in place of the Gear's Doc when its DataFunc returns an error. The error is logged
+// instead of failing the page, unless the DataFunc returns an error wrapped with Fatal().
+func Fallback(e html.Element) Option {
+	return func(g *Gear) {
+		if e == nil {
+			panic("Fallback() cannot be passed a nil Element")
+		}
+		g.fallback = &html.Doc{
+			Body:      &html.Body{Elements: []html.Element{e}},
+			Component: true,
+		}
+	}
+}
+
+// Fatal wraps an error returned by a DataFunc to indicate that the page cannot be rendered, even if the
+// Gear has a Fallback(). The error is passed to html.Pipeline.Error().
+func Fatal(err error) error {
+	if err == nil {
+		return nil
+	}
+	return fatalError{err: err}
+}
+
+// IsFatal returns true if the error or an error it wraps was returned by Fatal().
+func IsFatal(err error) bool {
+	return errors.As(err, &fatalError{})
+}
+
+type fatalError struct {
+	err error
+}
+
+func (f fatalError) Error() string {
+	return f.err.Error()
+}
+
+func (f fatalError) Unwrap() error {
+	return f.err
+}
+
+// failed is stored in html.Pipeline.GearData when the DataFunc failed and the Fallback should be rendered.
+type failed struct{}
+
+// dataErr handles an error from the DataFunc. It returns a non-nil error if the page cannot be rendered.
+func (g *Gear) dataErr(err error) error {
+	err = fmt.Errorf("WebGear Component(%s) DataFunc: %w", g.name, err)
+	if g.fallback == nil || IsFatal(err) {
+		return err
+	}
+	log.Printf("%s, rendering Fallback", err)
+	return nil
+}
+
+// ExecuteContent renders the Gear's Doc, or the Fallback if the DataFunc failed. This is for use by the
+// internal templates.
+func (g *Gear) ExecuteContent(pipe html.Pipeline) string {
+	if _, ok := pipe.GearData.(failed); ok {
+		return g.fallback.ExecuteAsGear(pipe)
+	}
+	return g.Doc.ExecuteAsGear(pipe)
+}
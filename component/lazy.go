@@ -0,0 +1,66 @@
+package component
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// LazyPath is the path that lazy loaded Gears are served under. A Gear named "my-gear" is served at
+// "/webgear/gear/my-gear".
+const LazyPath = "/webgear/gear/"
+
+// Lazy causes the Gear to output a small script instead of its template and loader. The template and loader,
+// along with those of every Gear added with AddGear(), are fetched the first time an instance of the component
+// scrolls into view or is interacted with. This reduces the size of pages that have many Gears.
+//
+// The content is served by the handlers.Mux the page is registered on. Handle() finds lazy Gears in the
+// html.Doc and registers them at LazyPath automatically. If the Gear is only added inside an html.Dynamic,
+// you must register LazyHandler() yourself. Lazy has no effect on Bundle().
+func Lazy() Option {
+	return func(g *Gear) {
+		g.lazy = true
+	}
+}
+
+// LazyURL is the URL path the Gear's content is fetched from when Lazy() is used.
+func (g *Gear) LazyURL() string {
+	return LazyPath + g.name
+}
+
+// LazyHandler returns the pattern and http.Handler that serves the Gear's content when Lazy() is used.
+// If the Gear is not lazy, pattern is the empty string and h is nil. This is used by handlers.Mux.
+func (g *Gear) LazyHandler() (pattern string, h http.Handler) {
+	if !g.lazy {
+		return "", nil
+	}
+
+	return g.LazyURL(), http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			buff := &strings.Builder{}
+			pipe := html.NewPipeline(r.Context(), r, buff)
+			err := g.render(
+				pipe,
+				false,
+				func(sheet *StyleSheet) error {
+					return gearTmpl.ExecuteTemplate(pipe.W, "sheet", sheet)
+				},
+				func(pipe html.Pipeline) error {
+					return gearTmpl.ExecuteTemplate(pipe.W, "combinedTxt", pipe)
+				},
+			)
+			if err == nil {
+				err = pipe.HadError()
+			}
+			if err != nil {
+				log.Printf("lazy load of %s: %s", g.LazyURL(), err)
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				return
+			}
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(buff.String()))
+		},
+	)
+}
@@ -0,0 +1,74 @@
+package component
+
+import (
+	"html/template"
+	"sort"
+)
+
+// Custom element lifecycle callbacks that a Gear can hook.
+const (
+	connectedCallback    = "connectedCallback"
+	disconnectedCallback = "disconnectedCallback"
+	adoptedCallback      = "adoptedCallback"
+)
+
+// OnConnected adds a Javascript snippet that is run each time the component is inserted into the DOM.
+// "this" is the custom element and "root" is its shadow root, which is also set with ClosedShadow().
+// This is commonly used to start timers or subscriptions. This may be passed more than once, the snippets
+// are run in order.
+//
+// Example:
+//
+//	component.OnConnected(`this.timer = setInterval(() => this.shadowRoot.getElementById("clock").textContent = new Date().toLocaleTimeString(), 1000);`)
+func OnConnected(snippet template.JS) Option {
+	return func(g *Gear) {
+		g.addHook(connectedCallback, snippet)
+	}
+}
+
+// OnDisconnected adds a Javascript snippet that is run each time the component is removed from the DOM.
+// This is used to stop anything started in OnConnected().
+//
+// Example:
+//
+//	component.OnDisconnected(`clearInterval(this.timer);`)
+func OnDisconnected(snippet template.JS) Option {
+	return func(g *Gear) {
+		g.addHook(disconnectedCallback, snippet)
+	}
+}
+
+// OnAdopted adds a Javascript snippet that is run each time the component is moved to a new document.
+func OnAdopted(snippet template.JS) Option {
+	return func(g *Gear) {
+		g.addHook(adoptedCallback, snippet)
+	}
+}
+
+func (g *Gear) addHook(callback string, snippet template.JS) {
+	if snippet == "" {
+		panic("a lifecycle hook cannot have an empty snippet")
+	}
+	if g.hooks == nil {
+		g.hooks = map[string][]template.JS{}
+	}
+	g.hooks[callback] = append(g.hooks[callback], snippet)
+}
+
+// Hook is a lifecycle callback of the custom element and the snippets it runs. This is used by the loader template.
+type Hook struct {
+	// Callback is the name of the custom element method, such as "connectedCallback".
+	Callback template.JS
+	// Snippets are the Javascript snippets run by the Callback.
+	Snippets []template.JS
+}
+
+// Hooks returns the lifecycle hooks registered on the Gear, sorted by Callback.
+func (g *Gear) Hooks() []Hook {
+	hooks := make([]Hook, 0, len(g.hooks))
+	for callback, snippets := range g.hooks {
+		hooks = append(hooks, Hook{Callback: template.JS(callback), Snippets: snippets})
+	}
+	sort.Slice(hooks, func(i, j int) bool { return hooks[i].Callback < hooks[j].Callback })
+	return hooks
+}
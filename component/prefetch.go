@@ -0,0 +1,106 @@
+package component
+
+import (
+	"fmt"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// maxDataFuncs is the maximum number of DataFuncs that run at the same time for a single page.
+const maxDataFuncs = 8
+
+// dataSemKey is the html.Pipeline key for the semaphore that limits the DataFuncs running for a page.
+const dataSemKey = "webgear:dataSem"
+
+// dataFuture is the result of a DataFunc that is running in the background.
+type dataFuture struct {
+	done chan struct{}
+	data interface{}
+	err  error
+}
+
+// Prefetch implements html.Prefetcher. It starts the DataFunc of the Gear and of every Gear added with AddGear()
+// in the background, so that they run in parallel instead of one after another as the page renders.
+// Gears using Lazy() are skipped, as they are not rendered with the page.
+// html.Doc calls this before rendering, so this does not need to be called by users.
+func (g *Gear) Prefetch(pipe html.Pipeline) {
+	g.prefetch(pipe, true, map[*Gear]bool{})
+}
+
+func (g *Gear) prefetch(pipe html.Pipeline, honorLazy bool, seen map[*Gear]bool) {
+	if seen[g] || (honorLazy && g.lazy) {
+		return
+	}
+	seen[g] = true
+
+	// The result with default props is always needed, as the Gear's <template> is rendered with them
+	// even if every html.Component sets props.
+	if g.dataFunc != nil {
+		g.future(g.withProps(pipe, nil))
+	}
+	for _, gear := range g.Gears {
+		gear.prefetch(pipe, honorLazy, seen)
+	}
+}
+
+// future returns the dataFuture for the Gear in this pipeline, starting the DataFunc if it hasn't been.
+func (g *Gear) future(pipe html.Pipeline) *dataFuture {
+	f := &dataFuture{done: make(chan struct{})}
+	v, loaded := pipe.LoadOrStore("data:"+g.name, f)
+	if loaded {
+		return v.(*dataFuture)
+	}
+
+	go g.fetch(pipe, f, dataSem(pipe))
+	return f
+}
+
+// dataSem returns the semaphore that limits the DataFuncs running for the page.
+func dataSem(pipe html.Pipeline) chan struct{} {
+	v, _ := pipe.LoadOrStore(dataSemKey, make(chan struct{}, maxDataFuncs))
+	return v.(chan struct{})
+}
+
+// fetch runs the DataFunc once a slot in sem is available and stores the result in f.
+func (g *Gear) fetch(pipe html.Pipeline, f *dataFuture, sem chan struct{}) {
+	defer close(f.done)
+	defer func() {
+		if r := recover(); r != nil {
+			f.err = fmt.Errorf("DataFunc panicked: %v", r)
+		}
+	}()
+
+	select {
+	case sem <- struct{}{}:
+		defer func() { <-sem }()
+	case <-pipe.Ctx.Done():
+		f.err = pipe.Ctx.Err()
+		return
+	}
+
+	// The request's context is the pipeline's, so the DataFunc can stop once the page is done.
+	req := pipe.Req
+	if req != nil {
+		req = req.WithContext(pipe.Ctx)
+	}
+	f.data, f.err = g.dataFunc(req)
+}
+
+// data returns the result of the DataFunc. If attrs set any props, the DataFunc is called for this
+// instance. Otherwise the result shared by all instances in the pipeline is used.
+func (g *Gear) data(pipe html.Pipeline, attrs []html.Attribute) (interface{}, error) {
+	if g.HasProps() && len(attrs) > 0 {
+		// This is run in the same way as a prefetch, so it shares the limit and panic handling.
+		f := &dataFuture{done: make(chan struct{})}
+		g.fetch(pipe, f, dataSem(pipe))
+		return f.data, f.err
+	}
+
+	f := g.future(pipe)
+	select {
+	case <-f.done:
+		return f.data, f.err
+	case <-pipe.Ctx.Done():
+		return nil, pipe.Ctx.Err()
+	}
+}
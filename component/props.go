@@ -0,0 +1,281 @@
+package component
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	stdhtml "html"
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// PropType is the type of value a Prop holds.
+type PropType int
+
+const (
+	// StringProp is a prop that holds any string value.
+	StringProp PropType = iota
+	// NumberProp is a prop that holds a value that can be parsed as a float64.
+	NumberProp
+	// BoolProp is a prop that holds "true" or "false". A BoolProp attribute with no value is true.
+	BoolProp
+)
+
+func (p PropType) String() string {
+	switch p {
+	case StringProp:
+		return "string"
+	case NumberProp:
+		return "number"
+	case BoolProp:
+		return "boolean"
+	}
+	return fmt.Sprintf("PropType(%d)", int(p))
+}
+
+// Prop describes an attribute that a Gear observes. When the attribute changes in the browser, elements
+// created with PropText() are updated and a "webgear-prop-changed" event is dispatched from the component.
+// Each Prop is also exposed as a Javascript property on the custom element. The property name is the
+// Name with hyphens removed and the following letter upper cased, so "max-items" becomes "maxItems".
+type Prop struct {
+	// Name is the attribute name. It must be lower case ascii letters, numbers and hyphens and start with a letter.
+	Name string
+	// Type is the type of the prop's value. This defaults to StringProp.
+	Type PropType
+	// Default is the value used when the attribute is not set on the html.Component.
+	Default string
+}
+
+func (p Prop) validate() error {
+	if err := validAttrName(p.Name); err != nil {
+		return fmt.Errorf("Prop(%s): %w", p.Name, err)
+	}
+	if err := p.validValue(p.Default); err != nil {
+		return fmt.Errorf("Prop(%s).Default: %w", p.Name, err)
+	}
+	return nil
+}
+
+// validValue validates that v is a valid value for the Prop.
+func (p Prop) validValue(v string) error {
+	switch p.Type {
+	case StringProp:
+	case NumberProp:
+		if v == "" {
+			return nil
+		}
+		if _, err := strconv.ParseFloat(v, 64); err != nil {
+			return fmt.Errorf("value %q is not a number", v)
+		}
+	case BoolProp:
+		switch v {
+		case "", "true", "false":
+		default:
+			return fmt.Errorf("value %q must be true or false", v)
+		}
+	default:
+		return fmt.Errorf("unknown PropType %v", p.Type)
+	}
+	return nil
+}
+
+// jsName is the name of the Javascript property for the Prop.
+func (p Prop) jsName() string {
+	parts := strings.Split(p.Name, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] == "" {
+			continue
+		}
+		parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+	}
+	return strings.Join(parts, "")
+}
+
+// Props declares the attributes that a Gear observes. Prop values can be read during server side rendering
+// in a DataFunc with PropsFromRequest() or in an html.DynamicFunc with PropsFromContext(). Without the
+// DeclarativeShadow() option, the Gear is rendered once for the page, so the values are always the defaults.
+func Props(props ...Prop) Option {
+	return func(g *Gear) {
+		g.props = append(g.props, props...)
+	}
+}
+
+// PropValues are the values for the props of a Gear, keyed by Prop.Name.
+type PropValues map[string]string
+
+// String returns the value of the prop as a string.
+func (p PropValues) String(name string) string {
+	return p[name]
+}
+
+// Number returns the value of the prop as a float64. If the value is not a number, 0 is returned.
+func (p PropValues) Number(name string) float64 {
+	f, _ := strconv.ParseFloat(p[name], 64)
+	return f
+}
+
+// Bool returns the value of the prop as a bool.
+func (p PropValues) Bool(name string) bool {
+	return p[name] == "true"
+}
+
+type propsKey struct{}
+
+// PropsFromContext returns the PropValues for the Gear being rendered. If the Gear has no props, this is nil.
+// Inside an html.DynamicFunc use the html.Pipeline.Ctx.
+func PropsFromContext(ctx context.Context) PropValues {
+	p, _ := ctx.Value(propsKey{}).(PropValues)
+	return p
+}
+
+// PropsFromRequest returns the PropValues for the Gear being rendered. This is for use in a DataFunc.
+func PropsFromRequest(r *http.Request) PropValues {
+	if r == nil {
+		return nil
+	}
+	return PropsFromContext(r.Context())
+}
+
+// ValidateAttributes implements html.AttributeValidator. It returns an error if any of the attributes
+// for a Prop have a value that is not valid for the Prop's Type.
+func (g *Gear) ValidateAttributes(attrs []html.Attribute) error {
+	if len(g.props) == 0 {
+		return nil
+	}
+	for _, a := range attrs {
+		name, value := attrNameValue(a)
+		for _, p := range g.props {
+			if p.Name != name {
+				continue
+			}
+			if p.Type == BoolProp && value == "" {
+				break
+			}
+			if err := p.validValue(value); err != nil {
+				return fmt.Errorf("attribute %s: %w", name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// propValues returns the PropValues for the attributes. The defaults are used for any prop not in attrs.
+func (g *Gear) propValues(attrs []html.Attribute) PropValues {
+	if len(g.props) == 0 {
+		return nil
+	}
+
+	values := make(PropValues, len(g.props))
+	for _, p := range g.props {
+		values[p.Name] = p.Default
+	}
+	for _, a := range attrs {
+		name, value := attrNameValue(a)
+		for _, p := range g.props {
+			if p.Name != name {
+				continue
+			}
+			if p.Type == BoolProp && value == "" {
+				value = "true"
+			}
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// withProps adds the PropValues for attrs to the pipe.Ctx and pipe.Req.
+func (g *Gear) withProps(pipe html.Pipeline, attrs []html.Attribute) html.Pipeline {
+	values := g.propValues(attrs)
+	if values == nil {
+		return pipe
+	}
+	pipe.Ctx = context.WithValue(pipe.Ctx, propsKey{}, values)
+	if pipe.Req == nil {
+		pipe.Req = &http.Request{}
+	}
+	pipe.Req = pipe.Req.WithContext(pipe.Ctx)
+	return pipe
+}
+
+type jsProp struct {
+	Name    string `json:"name"`
+	Prop    string `json:"prop"`
+	Type    string `json:"type"`
+	Default string `json:"def"`
+}
+
+// PropsJS outputs the Gear's props as a Javascript array for use in the loader.
+func (g *Gear) PropsJS() template.JS {
+	props := make([]jsProp, 0, len(g.props))
+	for _, p := range g.props {
+		props = append(props, jsProp{Name: p.Name, Prop: p.jsName(), Type: p.Type.String(), Default: p.Default})
+	}
+	b, err := json.Marshal(props)
+	if err != nil {
+		panic(err)
+	}
+	return template.JS(b)
+}
+
+// HasProps indicates if the Gear has any props.
+func (g *Gear) HasProps() bool {
+	return len(g.props) > 0
+}
+
+// PropText returns an element for use in a Gear's Doc that displays the value of the prop inside a <span>.
+// When the prop's attribute changes in the browser, the text is updated.
+func PropText(name string) html.Element {
+	return &propText{name: name}
+}
+
+type propText struct {
+	name string
+}
+
+func (p *propText) Execute(pipe html.Pipeline) string {
+	value := PropsFromContext(pipe.Ctx)[p.name]
+	fmt.Fprintf(
+		pipe.W,
+		`<span data-webgear-prop="%s">%s</span>`,
+		template.HTMLEscapeString(p.name),
+		template.HTMLEscapeString(value),
+	)
+	return html.EmptyString
+}
+
+// attrNameValue extracts the name and unescaped value from an html.Attribute.
+func attrNameValue(a html.Attribute) (name, value string) {
+	if attr, ok := a.(html.Attr); ok {
+		return attr.Name, attr.Value
+	}
+	s := a.String()
+	i := strings.Index(s, "=")
+	if i == -1 {
+		return strings.TrimSpace(s), ""
+	}
+	name = strings.TrimSpace(s[:i])
+	value = strings.Trim(strings.TrimSpace(s[i+1:]), `"'`)
+	return name, stdhtml.UnescapeString(value)
+}
+
+func validAttrName(s string) error {
+	if s == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	if s[0] < 'a' || s[0] > 'z' {
+		return fmt.Errorf("name must start with a lower case ascii letter")
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-':
+		default:
+			return fmt.Errorf("name cannot contain %q", r)
+		}
+	}
+	return nil
+}
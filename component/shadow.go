@@ -0,0 +1,94 @@
+package component
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// SlotAssignment is how the elements inside a component are assigned to its Slots.
+type SlotAssignment string
+
+const (
+	// NamedSlots assigns elements to slots by the slot attribute. This is the default.
+	NamedSlots SlotAssignment = "named"
+	// ManualSlots requires elements to be assigned with HTMLSlotElement.assign() in Javascript.
+	ManualSlots SlotAssignment = "manual"
+)
+
+// ClosedShadow attaches the component's shadow root in closed mode. The host page cannot reach the
+// component's internals through element.shadowRoot, which is useful for widgets embedded in third party pages.
+// Lifecycle hooks and props can still reach the shadow root. Pages rendered by WASM can give the html package
+// access to closed roots, so that WASM events can be attached to elements inside them. The loader hands the
+// roots to the first caller that asks for them, which is the html package when the page is rendered by WASM.
+// Pages rendered by a server never hand out the roots.
+func ClosedShadow() Option {
+	return func(g *Gear) {
+		g.closed = true
+	}
+}
+
+// DelegatesFocus causes focus on the component to be delegated to the first focusable element in its
+// shadow root, and styles the component with :focus when an element inside it has focus.
+func DelegatesFocus() Option {
+	return func(g *Gear) {
+		g.delegatesFocus = true
+	}
+}
+
+// AssignSlots sets how elements inside the component are assigned to Slots. This is not supported
+// when the shadow root is rendered with DeclarativeShadow(), where NamedSlots is always used.
+func AssignSlots(sa SlotAssignment) Option {
+	return func(g *Gear) {
+		switch sa {
+		case NamedSlots, ManualSlots:
+		default:
+			panic(fmt.Sprintf("AssignSlots(%q) is not a valid SlotAssignment", sa))
+		}
+		g.slotAssignment = sa
+	}
+}
+
+// Closed indicates the Gear uses a closed shadow root.
+func (g *Gear) Closed() bool {
+	return g.closed
+}
+
+// ConnectRoots indicates the loader of a Gear with a closed shadow root hands its roots to the WASM html
+// package. This is only true when the page is rendered by WASM.
+func (g *Gear) ConnectRoots() bool {
+	return connectRoots
+}
+
+// ShadowMode is the mode of the shadow root, "open" or "closed".
+func (g *Gear) ShadowMode() string {
+	if g.closed {
+		return "closed"
+	}
+	return "open"
+}
+
+// DelegatesFocus indicates that the shadow root delegates focus.
+func (g *Gear) DelegatesFocus() bool {
+	return g.delegatesFocus
+}
+
+// ShadowInit outputs the options object passed to attachShadow() in the loader.
+func (g *Gear) ShadowInit() template.JS {
+	opts := []string{fmt.Sprintf("mode: '%s'", g.ShadowMode())}
+	if g.delegatesFocus {
+		opts = append(opts, "delegatesFocus: true")
+	}
+	if g.slotAssignment != "" && g.slotAssignment != NamedSlots {
+		opts = append(opts, fmt.Sprintf("slotAssignment: '%s'", g.slotAssignment))
+	}
+	return template.JS("{" + strings.Join(opts, ", ") + "}")
+}
+
+// RootJS outputs the Javascript expression that gets the shadow root inside the custom element's methods.
+func (g *Gear) RootJS() template.JS {
+	if g.closed {
+		return "roots.get(this)"
+	}
+	return "this.shadowRoot"
+}
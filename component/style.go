@@ -0,0 +1,88 @@
+package component
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+)
+
+// StyleSheet is CSS that is output once per page and shared by every instance of the Gears that adopt it.
+// In browsers that support constructable stylesheets, the CSS is parsed once into a CSSStyleSheet and added to
+// each shadow root's adoptedStyleSheets. Otherwise a <style> element is added to each shadow root.
+type StyleSheet struct {
+	name string
+	css  string
+}
+
+// NewStyleSheet creates a StyleSheet called name that can be shared by multiple Gears with AdoptStyleSheets().
+// This is commonly used for design tokens, such as CSS custom properties, that all Gears use. name must be
+// lower case ascii letters, numbers and hyphens and start with a letter.
+func NewStyleSheet(name, css string) (*StyleSheet, error) {
+	if err := validAttrName(name); err != nil {
+		return nil, fmt.Errorf("StyleSheet(%s): %w", name, err)
+	}
+	return &StyleSheet{name: name, css: css}, nil
+}
+
+// Name is the name of the StyleSheet.
+func (s *StyleSheet) Name() string {
+	return s.name
+}
+
+// CSS is the content of the StyleSheet.
+func (s *StyleSheet) CSS() template.CSS {
+	return template.CSS(s.css)
+}
+
+// CSSText is the content of the StyleSheet for use as a Javascript string.
+func (s *StyleSheet) CSSText() string {
+	return s.css
+}
+
+// SharedStyle adds CSS that is applied to every instance of the Gear. Unlike an html.Style in the Gear's Doc,
+// the CSS is only output and parsed once per page. This may be passed more than once.
+func SharedStyle(css string) Option {
+	return func(g *Gear) {
+		// StyleSheet names cannot contain ":", so these never collide with names from NewStyleSheet().
+		name := fmt.Sprintf("gear:%s:%d", g.name, len(g.ownSheets))
+		g.ownSheets = append(g.ownSheets, &StyleSheet{name: name, css: css})
+	}
+}
+
+// AdoptStyleSheets applies StyleSheets shared with other Gears to every instance of the Gear. These are
+// applied before any SharedStyle().
+func AdoptStyleSheets(sheets ...*StyleSheet) Option {
+	return func(g *Gear) {
+		for _, s := range sheets {
+			if s == nil {
+				panic("AdoptStyleSheets() cannot be passed a nil *StyleSheet")
+			}
+		}
+		g.adopted = append(g.adopted, sheets...)
+	}
+}
+
+// Sheets returns all StyleSheets applied to the Gear, in the order they are applied.
+func (g *Gear) Sheets() []*StyleSheet {
+	sheets := make([]*StyleSheet, 0, len(g.adopted)+len(g.ownSheets))
+	sheets = append(sheets, g.adopted...)
+	return append(sheets, g.ownSheets...)
+}
+
+// HasSheets indicates if the Gear has any StyleSheets.
+func (g *Gear) HasSheets() bool {
+	return len(g.adopted)+len(g.ownSheets) > 0
+}
+
+// SheetNamesJS outputs the names of the Gear's StyleSheets as a Javascript array for use in the loader.
+func (g *Gear) SheetNamesJS() template.JS {
+	names := []string{}
+	for _, s := range g.Sheets() {
+		names = append(names, s.name)
+	}
+	b, err := json.Marshal(names)
+	if err != nil {
+		panic(err)
+	}
+	return template.JS(b)
+}
@@ -0,0 +1,309 @@
+package viewer
+
+import (
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+
+	"github.com/johnsiilver/webgear/component"
+	"github.com/johnsiilver/webgear/html"
+)
+
+// Story is a named example of a Gear, such as the Gear displaying one set of fixture data. When the Stories()
+// option is used, the Viewer serves an index of all stories at "/" and each story at "/story/<Name>".
+type Story struct {
+	// Name is the name of the story, which is used in its URL. It must be ascii letters, numbers, hyphens
+	// and underscores.
+	Name string
+	// Description is optional text displayed above the story.
+	Description string
+	// Gear is the Gear to display. Create it with the fixture data the story should show, either by passing
+	// the data to the Gear's constructor or with component.ApplyDataFunc().
+	Gear *component.Gear
+	// Doc is an optional Doc to display the Gear inside of, such as one that provides a parent element or
+	// CSS variables the Gear expects. The Gear is added to the end of Doc.Body.Elements.
+	Doc *html.Doc
+}
+
+func (s Story) validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("Story must have a Name")
+	}
+	for _, r := range s.Name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return fmt.Errorf("Story(%s): Name cannot contain %q", s.Name, r)
+		}
+	}
+	if s.Gear == nil {
+		return fmt.Errorf("Story(%s): Gear cannot be nil", s.Name)
+	}
+	return nil
+}
+
+// Stories adds stories to the Viewer. Each story is rendered in isolation inside an iframe, with a toolbar
+// to change the size of the iframe and the background color. If the *Gear passed to New() is not nil,
+// it is the first story and is named after the Gear.
+func Stories(stories ...Story) Option {
+	return func(v *Viewer) {
+		v.stories = append(v.stories, stories...)
+	}
+}
+
+// Viewport is a size that a story can be displayed at.
+type Viewport struct {
+	// Name is the name displayed in the toolbar.
+	Name string
+	// Width is the width in pixels. If 0, all of the available width is used.
+	Width uint
+	// Height is the height in pixels. If 0, a height based on the browser window is used.
+	Height uint
+}
+
+var (
+	// Desktop displays the story using all of the available space.
+	Desktop = Viewport{Name: "desktop"}
+	// Tablet displays the story at the size of a tablet in portrait mode.
+	Tablet = Viewport{Name: "tablet", Width: 768, Height: 1024}
+	// Mobile displays the story at the size of a phone in portrait mode.
+	Mobile = Viewport{Name: "mobile", Width: 375, Height: 667}
+)
+
+// viewports are the Viewports that can be chosen in the toolbar. The first is the default.
+var viewports = []Viewport{Desktop, Tablet, Mobile}
+
+// backgrounds are the background colors that can be chosen in the toolbar, along with any BackgroundColor().
+var backgrounds = []string{"white", "lightgray", "black"}
+
+const galleryCSS = `
+body { margin: 0; font-family: sans-serif; display: flex; min-height: 100vh; }
+nav { width: 14em; padding: 1em; border-right: 1px solid #ccc; background-color: #f5f5f5; }
+nav ul { list-style: none; padding: 0; }
+nav li { margin: 0.25em 0; }
+a.current { font-weight: bold; }
+.main { flex: 1; padding: 1em; }
+.toolbar a { margin-right: 0.75em; }
+.toolbar span { margin: 0 0.5em 0 1.5em; color: #666; }
+iframe { display: block; margin-top: 1em; border: 1px solid #ccc; }
+`
+
+// handleStories registers the index, the page for each story and the iframe content for each story.
+func (v *Viewer) handleStories() error {
+	names := map[string]bool{}
+	for _, s := range v.stories {
+		if err := s.validate(); err != nil {
+			return err
+		}
+		if names[s.Name] {
+			return fmt.Errorf("two Stories have the Name %q", s.Name)
+		}
+		names[s.Name] = true
+	}
+
+	if err := v.h.Handle("/", v.galleryDoc("webgear viewer", "", html.Dynamic(v.index))); err != nil {
+		return err
+	}
+	for _, s := range v.stories {
+		doc := v.galleryDoc(s.Name, s.Name, html.Dynamic(v.storyContent(s)))
+		if err := v.h.Handle(storyPath(s.Name), doc); err != nil {
+			return err
+		}
+		if err := v.h.Handle(storyPath(s.Name)+"/frame", v.frameDoc(s)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// galleryDoc returns a page with the list of stories on the side and content in the main area.
+// current is the name of the story being displayed, if any.
+func (v *Viewer) galleryDoc(title, current string, content html.Element) *html.Doc {
+	items := []html.Element{}
+	for _, s := range v.stories {
+		a := &html.A{Href: &url.URL{Path: storyPath(s.Name)}, Elements: []html.Element{html.EscapedText(s.Name)}}
+		if s.Name == current {
+			a.GlobalAttrs.Class = "current"
+		}
+		items = append(items, &html.Li{Elements: []html.Element{a}})
+	}
+
+	return &html.Doc{
+		Head: &html.Head{
+			Elements: []html.Element{
+				&html.Meta{Charset: "UTF-8"},
+				&html.Title{TagValue: html.TextElement(title)},
+				&html.Style{TagValue: template.CSS(galleryCSS)},
+			},
+		},
+		Body: &html.Body{
+			Elements: []html.Element{
+				&html.Nav{
+					Elements: []html.Element{
+						&html.A{Href: &url.URL{Path: "/"}, Elements: []html.Element{html.TextElement("Stories")}},
+						&html.Ul{Elements: items},
+					},
+				},
+				&html.Div{GlobalAttrs: html.GlobalAttrs{Class: "main"}, Elements: []html.Element{content}},
+			},
+		},
+	}
+}
+
+// index implements html.DynamicFunc to list all stories with their descriptions.
+func (v *Viewer) index(pipe html.Pipeline) []html.Element {
+	elements := []html.Element{&html.H{Level: 1, Elements: []html.Element{html.TextElement("Stories")}}}
+	for _, s := range v.stories {
+		elements = append(
+			elements,
+			&html.H{
+				Level: 2,
+				Elements: []html.Element{
+					&html.A{Href: &url.URL{Path: storyPath(s.Name)}, Elements: []html.Element{html.EscapedText(s.Name)}},
+				},
+			},
+		)
+		if s.Description != "" {
+			elements = append(elements, &html.P{Elements: []html.Element{html.EscapedText(s.Description)}})
+		}
+	}
+	return elements
+}
+
+// storyContent returns an html.DynamicFunc that outputs the toolbar and the iframe displaying the story.
+func (v *Viewer) storyContent(s Story) html.DynamicFunc {
+	return func(pipe html.Pipeline) []html.Element {
+		vp, bg := v.viewport(pipe.Req), v.background(pipe.Req)
+
+		toolbar := []html.Element{&html.Span{Elements: []html.Element{html.TextElement("Viewport:")}}}
+		for _, p := range viewports {
+			toolbar = append(toolbar, toolbarLink(p.Name, p.Name == vp.Name, storyURL(s.Name, "", p.Name, bg)))
+		}
+		toolbar = append(toolbar, &html.Span{Elements: []html.Element{html.TextElement("Background:")}})
+		for _, color := range v.backgrounds() {
+			toolbar = append(toolbar, toolbarLink(color, color == bg, storyURL(s.Name, "", vp.Name, color)))
+		}
+
+		style := "width: 100%; height: 80vh;"
+		if vp.Width != 0 {
+			style = fmt.Sprintf("width: %dpx; height: %dpx;", vp.Width, vp.Height)
+		}
+
+		elements := []html.Element{&html.H{Level: 1, Elements: []html.Element{html.EscapedText(s.Name)}}}
+		if s.Description != "" {
+			elements = append(elements, &html.P{Elements: []html.Element{html.EscapedText(s.Description)}})
+		}
+		return append(
+			elements,
+			&html.Div{GlobalAttrs: html.GlobalAttrs{Class: "toolbar"}, Elements: toolbar},
+			&html.IFrame{
+				GlobalAttrs: html.GlobalAttrs{Style: style},
+				Name:        s.Name,
+				Src:         storyURL(s.Name, "/frame", "", bg),
+			},
+		)
+	}
+}
+
+// frameDoc returns the Doc that displays the story in isolation inside the iframe. A new Doc is built
+// so that Story.Doc is not changed, as it may be shared by several stories.
+func (v *Viewer) frameDoc(s Story) *html.Doc {
+	doc := &html.Doc{
+		Head: &html.Head{
+			Elements: []html.Element{
+				&html.Meta{Charset: "UTF-8"},
+			},
+		},
+		Body: &html.Body{},
+	}
+	if s.Doc != nil {
+		if s.Doc.Head != nil {
+			head := *s.Doc.Head
+			head.Elements = append([]html.Element(nil), s.Doc.Head.Elements...)
+			doc.Head = &head
+		}
+		doc.GlobalAttrs = s.Doc.GlobalAttrs
+		doc.Events = s.Doc.Events
+		doc.Pretty = s.Doc.Pretty
+		if s.Doc.Body != nil {
+			body := *s.Doc.Body
+			doc.Body = &body
+		}
+	}
+
+	elements := []html.Element{html.Dynamic(v.frameColor)}
+	elements = append(elements, doc.Body.Elements...)
+	doc.Body.Elements = append(elements, s.Gear, &html.Component{Gear: s.Gear})
+	return doc
+}
+
+// frameColor implements html.DynamicFunc to set the background color chosen in the toolbar.
+func (v *Viewer) frameColor(pipe html.Pipeline) []html.Element {
+	return dynamicColor{v.background(pipe.Req)}.Color(pipe)
+}
+
+// viewport returns the Viewport chosen in the toolbar.
+func (v *Viewer) viewport(r *http.Request) Viewport {
+	if r != nil {
+		name := r.URL.Query().Get("viewport")
+		for _, p := range viewports {
+			if p.Name == name {
+				return p
+			}
+		}
+	}
+	return viewports[0]
+}
+
+// background returns the background color chosen in the toolbar. Only colors listed in the toolbar
+// are accepted, as the color is inserted into CSS.
+func (v *Viewer) background(r *http.Request) string {
+	if r != nil {
+		color := r.URL.Query().Get("bg")
+		for _, c := range v.backgrounds() {
+			if c == color {
+				return c
+			}
+		}
+	}
+	return v.backgrounds()[0]
+}
+
+// backgrounds returns the background colors for the toolbar, starting with the BackgroundColor() if set.
+func (v *Viewer) backgrounds() []string {
+	if v.color == "" {
+		return backgrounds
+	}
+	colors := []string{v.color}
+	for _, c := range backgrounds {
+		if c != v.color {
+			colors = append(colors, c)
+		}
+	}
+	return colors
+}
+
+func toolbarLink(name string, current bool, u *url.URL) *html.A {
+	a := &html.A{Href: u, Elements: []html.Element{html.EscapedText(name)}}
+	if current {
+		a.GlobalAttrs.Class = "current"
+	}
+	return a
+}
+
+func storyPath(name string) string {
+	return "/story/" + name
+}
+
+// storyURL returns the URL for the story. If viewport or bg are empty, they are not included.
+func storyURL(name, suffix, viewport, bg string) *url.URL {
+	values := url.Values{}
+	if viewport != "" {
+		values.Set("viewport", viewport)
+	}
+	if bg != "" {
+		values.Set("bg", bg)
+	}
+	return &url.URL{Path: storyPath(name) + suffix, RawQuery: values.Encode()}
+}
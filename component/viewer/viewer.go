@@ -25,24 +25,44 @@ Usage:
 		}
 
 		// Render it to 127.0.0.1:8080
-		v := viewer.New(
+		v, err := viewer.New(
 			*port,
 			nav,
 			viewer.BackgroundColor("black"),
 			viewer.ServeOtherFiles("../../../", []string{".css", ".jpg", ".svg", ".png"}),
 		)
+		if err != nil {
+			panic(err)
+		}
 
-		v.Run()
+		if err := v.Run(context.Background()); err != nil {
+			log.Fatal(err)
+		}
 	}
+
+Stories:
+
+To browse many components, or one component with different data, pass each as a Story. The index at "/" lists
+every story and each is displayed at "/story/<Name>" with a toolbar for choosing the viewport size and background.
+
+	v, err := viewer.New(
+		*port,
+		nil,
+		viewer.Stories(
+			viewer.Story{Name: "nav-one-video", Gear: navOne},
+			viewer.Story{Name: "nav-many-videos", Description: "Scrolls after 10 videos", Gear: navMany},
+		),
+	)
 */
 package viewer
 
 import (
+	"context"
 	"fmt"
 	"html/template"
-	"log"
+	"net"
 	"net/http"
-	"time"
+	"sync"
 
 	"github.com/johnsiilver/webgear/component"
 	"github.com/johnsiilver/webgear/handlers"
@@ -56,8 +76,13 @@ type Viewer struct {
 	doc   *html.Doc
 	color string
 
+	stories []Story
+
 	serveFrom *serveFrom
 	h         *handlers.Mux
+
+	mu       sync.Mutex
+	listener net.Listener
 }
 
 // Option provides an optional argument to New().
@@ -108,8 +133,9 @@ func (d dynamicColor) Color(pipe html.Pipeline) []html.Element {
 	return nil
 }
 
-// New constructs a new Viewer.
-func New(port int, gear *component.Gear, options ...Option) *Viewer {
+// New constructs a new Viewer. gear may be nil if the Stories() option is passed. If port is 0, a port
+// is chosen when the Viewer listens, which can be found with Listen().
+func New(port int, gear *component.Gear, options ...Option) (*Viewer, error) {
 	v := &Viewer{
 		port: port,
 		h:    handlers.New(handlers.DoNotCache()),
@@ -119,6 +145,21 @@ func New(port int, gear *component.Gear, options ...Option) *Viewer {
 		o(v)
 	}
 
+	if len(v.stories) > 0 {
+		if gear != nil {
+			v.stories = append([]Story{{Name: gear.Name(), Gear: gear, Doc: v.doc}}, v.stories...)
+		}
+		if err := v.handleStories(); err != nil {
+			return nil, err
+		}
+		v.serveOtherFiles()
+		return v, nil
+	}
+
+	if gear == nil {
+		return nil, fmt.Errorf("viewer.New() must be passed a *Gear or the Stories() option")
+	}
+
 	if v.doc == nil {
 		v.doc = &html.Doc{
 			Head: &html.Head{
@@ -136,28 +177,55 @@ func New(port int, gear *component.Gear, options ...Option) *Viewer {
 		}
 	}
 	if err := v.doc.Init(); err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	if err := v.h.Handle("/", v.doc); err != nil {
+		return nil, err
 	}
+	v.serveOtherFiles()
 
-	v.h.MustHandle("/", v.doc)
+	return v, nil
+}
+
+func (v *Viewer) serveOtherFiles() {
 	if v.serveFrom != nil {
 		v.h.ServeFilesFrom(v.serveFrom.from, "", v.serveFrom.exts)
 	}
+}
 
-	return v
+// Handler returns the http.Handler that serves the viewer. This can be used to serve the viewer
+// with an httptest.Server or mount it on another server.
+func (v *Viewer) Handler() http.Handler {
+	return v.h.ServerMux()
+}
+
+// Listen listens on the port passed to New() and returns the address listened on. This is useful when the port
+// is 0 to learn the port that was chosen. Calling this is optional, as Run() will listen if it has not been called.
+func (v *Viewer) Listen() (net.Addr, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if v.listener == nil {
+		l, err := net.Listen("tcp", fmt.Sprintf(":%d", v.port))
+		if err != nil {
+			return nil, err
+		}
+		v.listener = l
+	}
+	return v.listener.Addr(), nil
 }
 
-// Run runs the viewer and will block forever.
-func (v *Viewer) Run() {
-	server := &http.Server{
-		Addr:           fmt.Sprintf(":%d", v.port),
-		Handler:        v.h.ServerMux(),
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+// Run runs the viewer and blocks until ctx is canceled or an interrupt or SIGTERM is received.
+// A clean shutdown returns nil.
+func (v *Viewer) Run(ctx context.Context) error {
+	if _, err := v.Listen(); err != nil {
+		return err
 	}
 
-	log.Printf("http server serving on :%d", v.port)
+	v.mu.Lock()
+	l := v.listener
+	v.mu.Unlock()
 
-	log.Fatal(server.ListenAndServe())
+	return handlers.Serve(ctx, v.h, handlers.Listener(l))
 }
@@ -0,0 +1,174 @@
+package viewer
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/johnsiilver/webgear/component"
+	"github.com/johnsiilver/webgear/html"
+)
+
+func TestStories(t *testing.T) {
+	newGear := func(name, content string) *component.Gear {
+		g, err := component.New(name, &html.Doc{Body: &html.Body{Elements: []html.Element{html.TextElement(content)}}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+
+	// Both stories are displayed inside the same Doc, which must not be changed.
+	shared := &html.Doc{
+		Head: &html.Head{Elements: []html.Element{&html.Title{TagValue: html.TextElement("shared-title")}}},
+		Body: &html.Body{Elements: []html.Element{html.TextElement("shared-parent")}},
+	}
+
+	v, err := New(
+		0,
+		nil,
+		BackgroundColor("pink"),
+		Stories(
+			Story{Name: "empty", Description: "No <items>", Gear: newGear("my-list", "no-items"), Doc: shared},
+			Story{Name: "full", Gear: newGear("my-list", "many-items"), Doc: shared},
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shared.Body.Elements) != 1 {
+		t.Errorf("TestStories: Story.Doc was changed, Body has %d Elements, want 1", len(shared.Body.Elements))
+	}
+	if frame := v.frameDoc(Story{Gear: newGear("my-list", "no-items"), Doc: shared}); frame.Head == shared.Head {
+		t.Errorf("TestStories: the frame's Doc shares the Head of Story.Doc")
+	}
+
+	tests := []struct {
+		desc    string
+		path    string
+		want    []string
+		notWant []string
+	}{
+		{
+			desc: "Index",
+			path: "/",
+			want: []string{`href="/story/empty"`, `href="/story/full"`, "No &lt;items&gt;"},
+		},
+		{
+			desc: "Story page",
+			path: "/story/empty",
+			want: []string{
+				`<a href="/story/empty" class="current" >`,
+				`src="/story/empty/frame?bg=pink"`,
+				`href="/story/empty?bg=pink&viewport=mobile"`,
+				`href="/story/empty?bg=black&viewport=desktop"`,
+				"width: 100%",
+			},
+		},
+		{
+			desc: "Story page with viewport",
+			path: "/story/full?viewport=mobile&bg=black",
+			want: []string{`src="/story/full/frame?bg=black"`, "width: 375px; height: 667px;"},
+		},
+		{
+			desc:    "Frame",
+			path:    "/story/full/frame?bg=black",
+			want:    []string{"shared-title", "shared-parent", "many-items", "<my-list", "background-color: black"},
+			notWant: []string{"no-items"},
+		},
+		{
+			desc: "Frame ignores unknown background",
+			path: "/story/full/frame?bg=red;}",
+			want: []string{"background-color: pink"},
+		},
+	}
+
+	for _, test := range tests {
+		rec := httptest.NewRecorder()
+		v.Handler().ServeHTTP(rec, httptest.NewRequest("GET", test.path, nil))
+		b, _ := ioutil.ReadAll(rec.Body)
+		got := string(b)
+
+		for _, want := range test.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("TestStories(%s): output did not contain %q:\n%s", test.desc, want, got)
+			}
+		}
+		for _, notWant := range test.notWant {
+			if strings.Contains(got, notWant) {
+				t.Errorf("TestStories(%s): output contained %q", test.desc, notWant)
+			}
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	tests := []struct {
+		desc    string
+		options []Option
+	}{
+		{desc: "No Gear or Stories"},
+		{desc: "Story without a Name", options: []Option{Stories(Story{Gear: testGear(t)})}},
+		{desc: "Story Name with a space", options: []Option{Stories(Story{Name: "my story", Gear: testGear(t)})}},
+		{
+			desc: "Duplicate Story Names",
+			options: []Option{
+				Stories(Story{Name: "story", Gear: testGear(t)}, Story{Name: "story", Gear: testGear(t)}),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		if _, err := New(0, nil, test.options...); err == nil {
+			t.Errorf("TestNewErrors(%s): got err == nil, want err != nil", test.desc)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	v, err := New(0, testGear(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr, err := v.Listen()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- v.Run(ctx) }()
+
+	resp, err := http.Get("http://" + addr.String() + "/")
+	if err != nil {
+		t.Fatalf("TestRun: could not get page: %s", err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if !strings.Contains(string(b), "test-gear-content") {
+		t.Errorf("TestRun: page did not contain the Gear:\n%s", b)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("TestRun: got err == %s, want err == nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("TestRun: Run() did not return after the context was canceled")
+	}
+}
+
+func testGear(t *testing.T) *component.Gear {
+	g, err := component.New("test-gear", &html.Doc{Body: &html.Body{Elements: []html.Element{html.TextElement("test-gear-content")}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return g
+}
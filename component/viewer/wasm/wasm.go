@@ -1,6 +1,7 @@
 package wasm
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
@@ -21,8 +22,10 @@ type Viewer struct {
 	port       int
 	binName    string
 	useModules bool
+	watchDirs  []string
 
-	mu sync.Mutex
+	mu     sync.Mutex
+	events *events
 
 	serveFrom *serveFrom
 	h         *handlers.Mux
@@ -52,13 +55,15 @@ func UseModules() Option {
 }
 
 // New constructs a new Viewer. binName must be a .wasm file that has its main package
-// in the director ./main.  This will be built on startup. This can be rebuilt and
-// relaunched by simply hitting 'r' in the terminal.
+// in the director ./main.  This will be built on startup. This is rebuilt when a Go file
+// in the directories passed to WatchDirs() changes or by hitting 'r' in the terminal.
 func New(port int, binName string, options ...Option) *Viewer {
 	v := &Viewer{
-		port:    port,
-		binName: binName,
-		h:       handlers.New(handlers.DoNotCache()),
+		port:      port,
+		binName:   binName,
+		watchDirs: []string{"."},
+		events:    newEvents(),
+		h:         newMux(),
 	}
 
 	for _, o := range options {
@@ -74,17 +79,26 @@ func New(port int, binName string, options ...Option) *Viewer {
 		panic(err)
 	}
 
-	binHandle, err := wasmHTTP.Handler(u)
+	binHandle, err := wasmHTTP.Handler(u, wasmHTTP.HeadElements(reloadScript))
 	if err != nil {
 		panic(err)
 	}
 
+	v.routes(binHandle)
+	return v
+}
+
+func newMux() *handlers.Mux {
+	return handlers.New(handlers.DoNotCache())
+}
+
+// routes registers the handlers for the page, which is served by binHandle, and the build events.
+func (v *Viewer) routes(binHandle http.Handler) {
 	v.h.HTTPHandler("/", binHandle)
+	v.h.HTTPHandler(eventsPath, v.events)
 	if v.serveFrom != nil {
 		v.h.ServeFilesFrom(v.serveFrom.from, "", v.serveFrom.exts)
 	}
-
-	return v
 }
 
 func (v *Viewer) build() error {
@@ -106,12 +120,12 @@ func (v *Viewer) build() error {
 		for _, entry := range os.Environ() {
 			log.Println(entry)
 		}
-		return err
+		return fmt.Errorf("%s\n%s", err, out)
 	}
 	return nil
 }
 
-// Run runs the viewer and will block forever.
+// Run runs the viewer and blocks until an interrupt or SIGTERM is received.
 func (v *Viewer) Run() {
 	// disable input buffering
 	exec.Command("stty", "-F", "/dev/tty", "cbreak", "min", "1").Run()
@@ -120,26 +134,29 @@ func (v *Viewer) Run() {
 		for {
 			os.Stdin.Read(b)
 			if string(b) == "r" {
-				if err := v.build(); err == nil {
-					fmt.Println("wasm rebuild SUCCEEDED, reload web browser")
-				} else {
-					fmt.Println("wasm rebuild FAILED: ", err)
-				}
+				v.rebuild()
 			} else {
 				fmt.Printf("I don't know what %q is\n", string(b))
 			}
 		}
 	}()
 
-	server := &http.Server{
-		Addr:           fmt.Sprintf(":%d", v.port),
-		Handler:        v.h.ServerMux(),
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	log.Printf("http server serving on :%d", v.port)
+	if len(v.watchDirs) > 0 {
+		go v.watch(ctx)
+	}
 
-	log.Fatal(server.ListenAndServe())
+	// The write timeout is disabled and shutdown is short, as pages keep a connection open to receive rebuild events.
+	err := handlers.Serve(
+		ctx,
+		v.h,
+		handlers.Addr(fmt.Sprintf(":%d", v.port)),
+		handlers.Timeouts(10*time.Second, 0),
+		handlers.ShutdownTimeout(time.Second),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
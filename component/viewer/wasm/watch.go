@@ -0,0 +1,244 @@
+package wasm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// pollInterval is how often the watched directories are checked for changes.
+const pollInterval = 250 * time.Millisecond
+
+// debounce is how long the source files must be unchanged before a rebuild starts. This prevents
+// saving several files at once from causing several rebuilds.
+const debounce = 500 * time.Millisecond
+
+// eventsPath is where the page listens for the results of rebuilds.
+const eventsPath = "/webgear/viewer/events"
+
+// WatchDirs sets the directories that are watched for changes to Go source files. When a change is seen,
+// the wasm binary is rebuilt. If the build succeeds, the page in the browser reloads. If it fails, the
+// compiler output is displayed over the page. By default the current directory is watched.
+// Passing no directories turns off watching.
+func WatchDirs(dirs ...string) Option {
+	return func(v *Viewer) {
+		v.watchDirs = dirs
+	}
+}
+
+// fileState is used to detect that a file has changed.
+type fileState struct {
+	mod  time.Time
+	size int64
+}
+
+// snapshot returns the state of the Go source files below dirs. Directories starting with "." are skipped.
+func snapshot(dirs []string) (map[string]fileState, error) {
+	files := map[string]fileState{}
+	for _, dir := range dirs {
+		err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if info.IsDir() {
+				if p != dir && strings.HasPrefix(info.Name(), ".") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch {
+			case filepath.Ext(p) == ".go", info.Name() == "go.mod", info.Name() == "go.sum":
+				files[p] = fileState{mod: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// changed returns true if the files in a and b differ.
+func changed(a, b map[string]fileState) bool {
+	if len(a) != len(b) {
+		return true
+	}
+	for p, state := range a {
+		if other, ok := b[p]; !ok || !other.mod.Equal(state.mod) || other.size != state.size {
+			return true
+		}
+	}
+	return false
+}
+
+// watch rebuilds the wasm binary when the files in the watched directories change, until ctx is canceled.
+func (v *Viewer) watch(ctx context.Context) {
+	last, err := snapshot(v.watchDirs)
+	if err != nil {
+		log.Printf("cannot watch for changes: %s", err)
+		return
+	}
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	// lastChange is when a change was last seen that has not been built, the zero value if there is none.
+	var lastChange time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		files, err := snapshot(v.watchDirs)
+		if err != nil {
+			// Files are often missing for a moment while an editor saves them.
+			continue
+		}
+		if changed(last, files) {
+			last = files
+			lastChange = time.Now()
+			continue
+		}
+		if !lastChange.IsZero() && time.Since(lastChange) >= debounce {
+			lastChange = time.Time{}
+			v.rebuild()
+		}
+	}
+}
+
+// rebuild builds the wasm binary and tells the pages in the browser the result.
+func (v *Viewer) rebuild() {
+	if err := v.build(); err != nil {
+		fmt.Println("wasm rebuild FAILED: ", err)
+		v.events.send(buildEvent{name: "buildError", data: err.Error()})
+		return
+	}
+	fmt.Println("wasm rebuild SUCCEEDED, reloading web browser")
+	v.events.send(buildEvent{name: "reload"})
+}
+
+// buildEvent is a server-sent event with the result of a build.
+type buildEvent struct {
+	name string
+	data string
+}
+
+// events implements http.Handler to send buildEvents to pages in the browser.
+type events struct {
+	mu      sync.Mutex
+	clients map[chan buildEvent]bool
+	// failed is the last build error. It is sent to pages that connect while the build is broken.
+	failed *buildEvent
+}
+
+func newEvents() *events {
+	return &events{clients: map[chan buildEvent]bool{}}
+}
+
+// send sends the event to all connected pages.
+func (e *events) send(ev buildEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if ev.name == "buildError" {
+		e.failed = &ev
+	} else {
+		e.failed = nil
+	}
+
+	for ch := range e.clients {
+		select {
+		case ch <- ev:
+		default:
+			// The page hasn't read the last event, which already told it to reload or display an error.
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler.ServeHTTP.
+func (e *events) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	ch := make(chan buildEvent, 1)
+	e.mu.Lock()
+	e.clients[ch] = true
+	failed := e.failed
+	e.mu.Unlock()
+
+	defer func() {
+		e.mu.Lock()
+		delete(e.clients, ch)
+		e.mu.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	if failed != nil {
+		if err := writeEvent(w, *failed); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case ev := <-ch:
+			if err := writeEvent(w, ev); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w io.Writer, ev buildEvent) error {
+	b, err := json.Marshal(ev.data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.name, b)
+	return err
+}
+
+// reloadScript listens for the results of rebuilds. It reloads the page after a successful build and
+// displays the compiler output over the page after a failed build.
+var reloadScript = &html.Script{
+	TagValue: template.JS(`
+(() => {
+	const events = new EventSource("` + eventsPath + `");
+	events.addEventListener("reload", () => location.reload());
+	events.addEventListener("buildError", (e) => {
+		let overlay = document.getElementById("webgear-build-error");
+		if (overlay === null) {
+			overlay = document.createElement("pre");
+			overlay.id = "webgear-build-error";
+			overlay.style.cssText = "position: fixed; top: 0; left: 0; right: 0; bottom: 0; margin: 0; padding: 1em; " +
+				"overflow: auto; z-index: 2147483647; white-space: pre-wrap; font: 14px monospace; " +
+				"color: #ff8080; background-color: rgba(0, 0, 0, 0.9);";
+			document.documentElement.appendChild(overlay);
+		}
+		overlay.textContent = "wasm build failed:\n\n" + JSON.parse(e.data);
+	});
+})();
+`),
+}
@@ -0,0 +1,140 @@
+package wasm
+
+import (
+	"bufio"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSnapshot(t *testing.T) {
+	dir, err := ioutil.TempDir("", "webgear-watch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, content string) {
+		p := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(p, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("main/main.go", "package main")
+	before, err := snapshot([]string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		desc  string
+		write func()
+		want  bool
+	}{
+		{desc: "No change", write: func() {}, want: false},
+		{desc: "Non-Go file", write: func() { write("main/main.wasm", "binary") }, want: false},
+		{desc: "Hidden directory", write: func() { write(".git/x.go", "package x") }, want: false},
+		{desc: "Go file changed", write: func() { write("main/main.go", "package main // changed") }, want: true},
+		{desc: "Go file added", write: func() { write("comp.go", "package comp") }, want: true},
+	}
+
+	for _, test := range tests {
+		test.write()
+		after, err := snapshot([]string{dir})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := changed(before, after); got != test.want {
+			t.Errorf("TestSnapshot(%s): got changed == %v, want %v", test.desc, got, test.want)
+		}
+		before = after
+	}
+}
+
+func TestEvents(t *testing.T) {
+	e := newEvents()
+	e.send(buildEvent{name: "buildError", data: "main.go:1: syntax error"})
+
+	// The events are served by the same Mux as New() uses, which compresses responses for browsers.
+	v := &Viewer{events: e, h: newMux()}
+	v.routes(http.NotFoundHandler())
+
+	srv := httptest.NewServer(v.h.ServerMux())
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+eventsPath, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept-Encoding", "gzip")
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("TestEvents: got status %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	// Setting Accept-Encoding stops the client from decompressing the body for us.
+	var body io.Reader = resp.Body
+	if resp.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body = gz
+	}
+
+	if got := resp.Header.Get("Content-Type"); got != "text/event-stream" {
+		t.Errorf("TestEvents: got Content-Type %q, want %q", got, "text/event-stream")
+	}
+
+	lines := make(chan string)
+	go func() {
+		scanner := bufio.NewScanner(body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+		close(lines)
+	}()
+
+	readEvent := func() string {
+		ev := []string{}
+		for {
+			select {
+			case line := <-lines:
+				if line == "" {
+					return strings.Join(ev, "\n")
+				}
+				ev = append(ev, line)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("TestEvents: timed out waiting for an event")
+			}
+		}
+	}
+
+	// A page that connects while the build is broken is sent the last error.
+	want := "event: buildError\ndata: \"main.go:1: syntax error\""
+	if got := readEvent(); got != want {
+		t.Errorf("TestEvents(connect): got %q, want %q", got, want)
+	}
+
+	e.send(buildEvent{name: "reload"})
+	want = "event: reload\ndata: \"\""
+	if got := readEvent(); got != want {
+		t.Errorf("TestEvents(reload): got %q, want %q", got, want)
+	}
+}
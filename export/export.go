@@ -0,0 +1,357 @@
+/*
+Package export renders a site built with handlers.Mux into a directory of plain files that can be served by
+any static file host.
+
+Every route registered with Mux.Handle() is rendered through the Mux's real handler chain without a network
+listener. Files served by Mux.ServeFS(), Mux.ServeFilesWorkingDir() and Mux.ServeFilesFrom() are copied. Routes
+registered with Mux.HTTPHandler() are exported only if their pattern is a file, such as "/robots.txt".
+Root relative links in the rendered HTML, such as href="/static/index.css", are rewritten to relative links so
+that the site can be hosted from any directory. This covers the href, src, action, formaction, poster and srcset
+attributes. Links inside CSS or Javascript, such as url() in a <style>, are not rewritten.
+
+Usage:
+	h := handlers.New()
+	h.ServeFilesWorkingDir([]string{".css", ".jpg", ".svg", ".png"})
+	h.MustHandle("/", index)
+	h.MustHandle("/blog/", blog)
+
+	// "/blog/" renders different content for each post, so list the posts we want exported.
+	err := export.Export(ctx, h, "./public", export.ExtraURLs("/blog/first-post", "/blog/second-post"))
+	if err != nil {
+		// Do something
+	}
+*/
+package export
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/johnsiilver/webgear/handlers"
+	xhtml "golang.org/x/net/html"
+)
+
+// Option is an optional argument to Export().
+type Option func(e *exporter)
+
+// ExtraURLs are URL paths to render in addition to the patterns registered on the Mux. This is
+// used for patterns that serve more than one page, such as "/blog/" or "/users/{id}".
+func ExtraURLs(paths ...string) Option {
+	return func(e *exporter) {
+		e.extra = append(e.extra, paths...)
+	}
+}
+
+type exporter struct {
+	mux     *handlers.Mux
+	handler http.Handler
+	out     string
+	extra   []string
+
+	// pages are the URL paths of all rendered pages.
+	pages map[string]bool
+}
+
+// Export renders all Doc routes on the Mux and writes them along with all static files to directory out.
+func Export(ctx context.Context, m *handlers.Mux, out string, options ...Option) error {
+	out, err := filepath.Abs(out)
+	if err != nil {
+		return err
+	}
+
+	e := &exporter{
+		mux:     m,
+		handler: m.ServerMux(),
+		out:     out,
+		pages:   map[string]bool{},
+	}
+	for _, o := range options {
+		o(e)
+	}
+
+	if err := os.MkdirAll(out, 0755); err != nil {
+		return err
+	}
+
+	pages, files, err := e.paths()
+	if err != nil {
+		return err
+	}
+	for _, p := range pages {
+		e.pages[p] = true
+	}
+
+	for _, p := range pages {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b, err := e.get(ctx, p)
+		if err != nil {
+			return err
+		}
+		name := pageFile(p)
+		if err := e.write(name, rewriteLinks(b, name, e.pages)); err != nil {
+			return err
+		}
+	}
+
+	for _, p := range files {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		b, err := e.get(ctx, p)
+		if err != nil {
+			return err
+		}
+		if err := e.write(strings.TrimPrefix(p, "/"), b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// paths returns the URL paths of all pages that need to be rendered and all files that need to be copied.
+func (e *exporter) paths() (pages []string, files []string, err error) {
+	for _, route := range e.mux.Routes() {
+		pattern := route.Pattern
+		// Go 1.22 style patterns can start with a method, such as "GET /".
+		if i := strings.Index(pattern, " "); i != -1 {
+			pattern = strings.TrimSpace(pattern[i+1:])
+		}
+		if !strings.HasPrefix(pattern, "/") {
+			continue
+		}
+
+		switch route.Kind {
+		case handlers.DocRoute:
+			if strings.Contains(pattern, "{") {
+				continue
+			}
+			pages = append(pages, pattern)
+		case handlers.HTTPRoute:
+			if path.Ext(pattern) != "" && !strings.Contains(pattern, "{") {
+				files = append(files, pattern)
+			}
+		case handlers.FSRoute:
+			err := fs.WalkDir(route.FS, ".", func(p string, d fs.DirEntry, err error) error {
+				if err != nil {
+					return err
+				}
+				if d.IsDir() {
+					// A directory is only blocked if it is a dot file, which blocks everything in it.
+					if p != "." && strings.HasPrefix(d.Name(), ".") {
+						return fs.SkipDir
+					}
+					return nil
+				}
+				// The Mux would refuse to serve these, such as "img/.DS_Store" or files a FilePolicy denies.
+				if !route.Serves(p) {
+					return nil
+				}
+				files = append(files, pattern+p)
+				return nil
+			})
+			if err != nil {
+				return nil, nil, err
+			}
+		case handlers.FilesRoute:
+			fl, err := e.dirFiles(pattern, route)
+			if err != nil {
+				return nil, nil, err
+			}
+			files = append(files, fl...)
+		}
+	}
+
+	for _, p := range e.extra {
+		if !strings.HasPrefix(p, "/") {
+			return nil, nil, fmt.Errorf("ExtraURLs(%s) must start with /", p)
+		}
+		pages = append(pages, p)
+	}
+	return dedup(pages), dedup(files), nil
+}
+
+// dirFiles returns the URL paths for all files in the route's directory that the Mux serves.
+func (e *exporter) dirFiles(pattern string, route handlers.Route) ([]string, error) {
+	files := []string{}
+	err := filepath.Walk(route.Dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		// Don't export our own output if it is inside the directory being served.
+		if abs, err := filepath.Abs(p); err == nil && abs == e.out {
+			return filepath.SkipDir
+		}
+		rel, err := filepath.Rel(route.Dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if info.IsDir() {
+			// A directory is only blocked if it is a dot file, which blocks everything in it.
+			if rel != "." && strings.HasPrefix(info.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !route.Serves(rel) {
+			return nil
+		}
+		files = append(files, pattern+rel)
+		return nil
+	})
+	return files, err
+}
+
+// get retrieves the content at URL path p by running it through the Mux.
+func (e *exporter) get(ctx context.Context, p string) ([]byte, error) {
+	req := httptest.NewRequest(http.MethodGet, p, nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+	e.handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		return nil, fmt.Errorf("export of %s returned status code %d", p, w.Code)
+	}
+	return io.ReadAll(w.Body)
+}
+
+// write writes b to the file at name, which is a slash separated path relative to the output directory.
+func (e *exporter) write(name string, b []byte) error {
+	p := filepath.Join(e.out, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0644)
+}
+
+// pageFile returns the file a page at URL path p is written to, relative to the output directory.
+// Paths without an extension become a directory with an index.html file.
+func pageFile(p string) string {
+	if path.Ext(p) != "" && !strings.HasSuffix(p, "/") {
+		return strings.TrimPrefix(p, "/")
+	}
+	return strings.TrimPrefix(path.Join(p, "index.html"), "/")
+}
+
+// linkAttrs are the attributes that hold a URL. srcset holds a list of URLs and is handled separately.
+var linkAttrs = map[string]bool{"href": true, "src": true, "action": true, "formaction": true, "poster": true}
+
+// rewriteLinks makes all root relative links in the html relative to the file it will be written to.
+// Links to pages that were exported are pointed at the page's file. Links in the attributes in linkAttrs and
+// in srcset are rewritten. Links inside CSS or Javascript, such as url() in a <style>, are not.
+// Tags without a root relative link are written as they were rendered.
+func rewriteLinks(b []byte, file string, pages map[string]bool) []byte {
+	from := path.Dir(file)
+	out := &bytes.Buffer{}
+
+	z := xhtml.NewTokenizer(bytes.NewReader(b))
+	for {
+		tt := z.Next()
+		if tt == xhtml.ErrorToken {
+			break
+		}
+		raw := z.Raw()
+		if tt != xhtml.StartTagToken && tt != xhtml.SelfClosingTagToken {
+			out.Write(raw)
+			continue
+		}
+
+		// Raw() is only valid until the next call, so copy it before Token() parses the attributes.
+		raw = append([]byte(nil), raw...)
+		tok := z.Token()
+		changed := false
+		for i, a := range tok.Attr {
+			var v string
+			switch {
+			case a.Namespace != "":
+				continue
+			case linkAttrs[a.Key]:
+				v = relLink(a.Val, from, pages)
+			case a.Key == "srcset":
+				v = relSrcSet(a.Val, from, pages)
+			default:
+				continue
+			}
+			if v != a.Val {
+				tok.Attr[i].Val = v
+				changed = true
+			}
+		}
+		if !changed {
+			out.Write(raw)
+			continue
+		}
+		out.WriteString(tok.String())
+	}
+	return out.Bytes()
+}
+
+// relLink returns link relative to the directory from if it is root relative. Otherwise link is returned.
+func relLink(link, from string, pages map[string]bool) string {
+	if !strings.HasPrefix(link, "/") || strings.HasPrefix(link, "//") {
+		return link
+	}
+
+	suffix := ""
+	p := link
+	if i := strings.IndexAny(p, "?#"); i != -1 {
+		p, suffix = p[:i], p[i:]
+	}
+
+	target := strings.TrimPrefix(p, "/")
+	if pages[p] || strings.HasSuffix(p, "/") || path.Ext(p) == "" {
+		target = pageFile(p)
+	}
+
+	rel, err := filepath.Rel(filepath.FromSlash(from), filepath.FromSlash(target))
+	if err != nil {
+		return link
+	}
+	return filepath.ToSlash(rel) + suffix
+}
+
+// relSrcSet applies relLink() to each URL in a srcset, such as "/a.png 1x, /b.png 2x".
+func relSrcSet(srcset, from string, pages map[string]bool) string {
+	changed := false
+	candidates := strings.Split(srcset, ",")
+	for i, c := range candidates {
+		fields := strings.Fields(c)
+		if len(fields) == 0 {
+			continue
+		}
+		if rel := relLink(fields[0], from, pages); rel != fields[0] {
+			fields[0] = rel
+			changed = true
+		}
+		candidates[i] = strings.Join(fields, " ")
+	}
+	if !changed {
+		return srcset
+	}
+	return strings.Join(candidates, ", ")
+}
+
+func dedup(l []string) []string {
+	seen := map[string]bool{}
+	out := []string{}
+	for _, s := range l {
+		if seen[s] {
+			continue
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	sort.Strings(out)
+	return out
+}
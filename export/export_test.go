@@ -0,0 +1,173 @@
+package export
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/johnsiilver/webgear/handlers"
+	"github.com/johnsiilver/webgear/html"
+)
+
+func TestExport(t *testing.T) {
+	static := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(static, "css"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(static, "css", "index.css"), []byte("body{}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(static, "secret.txt"), []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	// The Mux blocks these, so they must be skipped instead of failing the export.
+	for _, name := range []string{".gitkeep", filepath.Join("css", ".DS_Store"), filepath.Join("css", "private.css")} {
+		if err := os.WriteFile(filepath.Join(static, name), []byte("hidden"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	newDoc := func() *html.Doc {
+		return &html.Doc{
+			Head: &html.Head{
+				Elements: []html.Element{
+					&html.Title{TagValue: html.TextElement("title")},
+					&html.Link{Rel: "stylesheet", Href: html.URLParse("/static/css/index.css")},
+				},
+			},
+			Body: &html.Body{
+				Elements: []html.Element{
+					&html.A{Href: html.URLParse("/blog/first#top"), Elements: []html.Element{html.TextElement("first")}},
+					&html.A{Href: html.URLParse("https://golang.org/"), Elements: []html.Element{html.TextElement("go")}},
+				},
+			},
+		}
+	}
+
+	m := handlers.New(
+		handlers.RobotsTxt("User-agent: *"),
+		handlers.StaticFilePolicy(&handlers.FilePolicy{Allow: []string{"*.gitkeep", "*.DS_Store"}, Deny: []string{"private.css"}}),
+	)
+	m.ServeFilesFrom(static, "", []string{".css"})
+	m.MustHandle("/", newDoc())
+	m.MustHandle("/blog/", newDoc())
+
+	out := t.TempDir()
+	if err := Export(context.Background(), m, out, ExtraURLs("/blog/first")); err != nil {
+		t.Fatalf("TestExport: got err == %s, want err == nil", err)
+	}
+
+	tests := []struct {
+		desc    string
+		file    string
+		want    []string
+		missing bool
+	}{
+		{
+			desc: "Index page",
+			file: "index.html",
+			want: []string{`href="static/css/index.css"`, `href="blog/first/index.html#top"`, `href="https://golang.org/"`},
+		},
+		{
+			desc: "Blog page",
+			file: "blog/first/index.html",
+			want: []string{`href="../../static/css/index.css"`, `href="index.html#top"`},
+		},
+		{desc: "Blog index", file: "blog/index.html"},
+		{desc: "Static file", file: "static/css/index.css", want: []string{"body{}"}},
+		{desc: "robots.txt", file: "robots.txt", want: []string{"User-agent: *"}},
+		{desc: "Not allowed file", file: "static/secret.txt", missing: true},
+		{desc: "Dot file", file: "static/.gitkeep", missing: true},
+		{desc: "Dot file in directory", file: "static/css/.DS_Store", missing: true},
+		{desc: "Denied file", file: "static/css/private.css", missing: true},
+	}
+
+	for _, test := range tests {
+		b, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(test.file)))
+		switch {
+		case test.missing && err == nil:
+			t.Errorf("TestExport(%s): file %s was exported, but should not have been", test.desc, test.file)
+			continue
+		case test.missing:
+			continue
+		case err != nil:
+			t.Errorf("TestExport(%s): could not read %s: %s", test.desc, test.file, err)
+			continue
+		}
+
+		for _, s := range test.want {
+			if !strings.Contains(string(b), s) {
+				t.Errorf("TestExport(%s): %s did not contain %q:\n%s", test.desc, test.file, s, b)
+			}
+		}
+	}
+}
+
+func TestExportFS(t *testing.T) {
+	files := fstest.MapFS{
+		"img/logo.png":   &fstest.MapFile{Data: []byte("png")},
+		"img/.DS_Store":  &fstest.MapFile{Data: []byte("hidden")},
+		".git/HEAD":      &fstest.MapFile{Data: []byte("hidden")},
+		"img/secret.key": &fstest.MapFile{Data: []byte("secret")},
+	}
+
+	m := handlers.New(handlers.StaticFilePolicy(&handlers.FilePolicy{Allow: []string{"*.png"}}))
+	m.ServeFS(files)
+
+	out := t.TempDir()
+	if err := Export(context.Background(), m, out); err != nil {
+		t.Fatalf("TestExportFS: got err == %s, want err == nil", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(out, "static", "img", "logo.png")); err != nil {
+		t.Errorf("TestExportFS: static/img/logo.png was not exported: %s", err)
+	}
+	for _, name := range []string{"static/img/.DS_Store", "static/.git/HEAD", "static/img/secret.key"} {
+		if _, err := os.Stat(filepath.Join(out, filepath.FromSlash(name))); err == nil {
+			t.Errorf("TestExportFS: %s was exported, but should not have been", name)
+		}
+	}
+}
+
+func TestRewriteLinks(t *testing.T) {
+	pages := map[string]bool{"/blog/first": true}
+
+	tests := []struct {
+		desc string
+		html string
+		want string
+	}{
+		{
+			desc: "Double quoted",
+			html: `<a href="/blog/first#top">x</a>`,
+			want: `<a href="../first/index.html#top">x</a>`,
+		},
+		{
+			desc: "Single quoted and unquoted",
+			html: `<img src='/static/a.png'><link href=/static/a.css rel=stylesheet>`,
+			want: `<img src="../../static/a.png"><link href="../../static/a.css" rel="stylesheet">`,
+		},
+		{
+			desc: "srcset",
+			html: `<img srcset="/static/a.png 1x, /static/b.png 2x">`,
+			want: `<img srcset="../../static/a.png 1x, ../../static/b.png 2x">`,
+		},
+		{
+			desc: "Other links and text are not changed",
+			html: `<p  class="x">a &amp; b</p><a href="https://golang.org/">g</a><a href="//cdn.example.com/a.js">c</a>` +
+				`<script>if (a < b) { x = "/static/a.png"; }</script>`,
+			want: `<p  class="x">a &amp; b</p><a href="https://golang.org/">g</a><a href="//cdn.example.com/a.js">c</a>` +
+				`<script>if (a < b) { x = "/static/a.png"; }</script>`,
+		},
+	}
+
+	for _, test := range tests {
+		got := string(rewriteLinks([]byte(test.html), "blog/second/index.html", pages))
+		if got != test.want {
+			t.Errorf("TestRewriteLinks(%s): \n\tgot  %s\n\twant %s", test.desc, got, test.want)
+		}
+	}
+}
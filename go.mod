@@ -7,6 +7,7 @@ require (
 	github.com/grpc-ecosystem/grpc-gateway v1.14.8
 	github.com/johnsiilver/go_basics v0.0.0-20200612183708-9254a13bbede
 	github.com/kylelemons/godebug v1.1.0
+	golang.org/x/net v0.0.0-20200602114024-627f9648deb9
 	google.golang.org/genproto v0.0.0-20200513103714-09dca8ec2884
 	google.golang.org/grpc v1.31.1
 	google.golang.org/protobuf v1.23.0
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// debugPattern is where the DebugPage() option serves the debug page.
+const debugPattern = "/debug/webgear"
+
+const debugCSS = `
+body { font-family: monospace; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { border: 1px solid #ccc; padding: 2px 8px; text-align: left; vertical-align: top; }
+.gear { color: #8a2be2; }
+.dynamic { color: #d2691e; }
+`
+
+// DebugHandler returns an http.Handler that renders a page listing all routes registered on the Mux and, for each
+// *html.Doc, the element tree including Gears, their shadow paths, dynamic elements and attached events.
+// This is served at /debug/webgear if the DebugPage() option is used.
+func (m *Mux) DebugHandler() http.Handler {
+	doc := &html.Doc{
+		Head: &html.Head{
+			Elements: []html.Element{
+				&html.Meta{Charset: "UTF-8"},
+				&html.Title{TagValue: html.TextElement("webgear debug")},
+				&html.Style{TagValue: template.CSS(debugCSS)},
+			},
+		},
+		Body: &html.Body{
+			Elements: []html.Element{
+				html.Dynamic(m.debugContent),
+			},
+		},
+	}
+	if err := doc.Init(); err != nil {
+		panic(err)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if err := doc.Execute(r.Context(), w, r); err != nil {
+			log.Printf("problem rendering debug page: %s", err)
+		}
+	})
+}
+
+// debugContent implements html.DynamicFunc to output the route table and the element tree of each Doc.
+func (m *Mux) debugContent(pipe html.Pipeline) []html.Element {
+	routes := m.Routes()
+
+	rows := []*html.TR{}
+	for _, route := range routes {
+		rows = append(
+			rows,
+			&html.TR{
+				Elements: []html.TRElement{
+					&html.TD{Element: html.EscapedText(route.Pattern)},
+					&html.TD{Element: html.EscapedText(route.Kind.String())},
+					&html.TD{Element: html.EscapedText(routeDetail(route))},
+				},
+			},
+		)
+	}
+
+	elements := []html.Element{
+		&html.H{Level: 1, Elements: []html.Element{html.TextElement("Routes")}},
+		&html.Table{
+			Elements: []html.TableElement{
+				&html.THead{
+					Elements: []*html.TR{
+						{
+							Elements: []html.TRElement{
+								&html.TH{Element: "Pattern"},
+								&html.TH{Element: "Kind"},
+								&html.TH{Element: "Detail"},
+							},
+						},
+					},
+				},
+				&html.TBody{Elements: rows},
+			},
+		},
+	}
+
+	for _, route := range routes {
+		if pipe.Ctx.Err() != nil {
+			return nil
+		}
+		if route.Kind != DocRoute {
+			continue
+		}
+		elements = append(
+			elements,
+			&html.H{Level: 2, Elements: []html.Element{html.EscapedText(route.Pattern)}},
+			docTree(pipe.Ctx, route.Doc),
+		)
+	}
+	return elements
+}
+
+// docTree returns a table detailing every Element in the doc as found by html.Walker().
+func docTree(ctx context.Context, doc *html.Doc) html.Element {
+	rows := []*html.TR{}
+
+	roots := []html.Element{}
+	if doc.Head != nil {
+		roots = append(roots, doc.Head)
+	}
+	roots = append(roots, doc.Body)
+
+	for _, root := range roots {
+		for walked := range html.Walker(ctx, root) {
+			name, class := elementDesc(walked.Element)
+			events := ""
+			if e := html.ExtractEvents(walked.Element); e != nil {
+				events = string(e.Attr())
+			}
+
+			rows = append(
+				rows,
+				&html.TR{
+					Elements: []html.TRElement{
+						&html.TD{
+							GlobalAttrs: html.GlobalAttrs{
+								Class: class,
+								Style: fmt.Sprintf("padding-left: %dem", walked.Depth*2+1),
+							},
+							Element: html.EscapedText(name),
+						},
+						&html.TD{Element: html.EscapedText(html.GetElementID(walked.Element))},
+						&html.TD{Element: html.EscapedText(strings.Join(walked.ShadowPath, " > "))},
+						&html.TD{Element: html.EscapedText(events)},
+					},
+				},
+			)
+		}
+	}
+
+	return &html.Table{
+		Elements: []html.TableElement{
+			&html.THead{
+				Elements: []*html.TR{
+					{
+						Elements: []html.TRElement{
+							&html.TH{Element: "Element"},
+							&html.TH{Element: "ID"},
+							&html.TH{Element: "Shadow Path"},
+							&html.TH{Element: "Events"},
+						},
+					},
+				},
+			},
+			&html.TBody{Elements: rows},
+		},
+	}
+}
+
+// elementDesc returns a description of the Element and the CSS class to display it with.
+func elementDesc(e html.Element) (desc string, class string) {
+	switch v := e.(type) {
+	case html.GearType:
+		return fmt.Sprintf("Gear(%s)", v.Name()), "gear"
+	case *html.Component:
+		if v.Gear != nil {
+			return fmt.Sprintf("Component(%s)", v.Gear.Name()), "gear"
+		}
+	case html.TextElement:
+		s := strings.TrimSpace(string(v))
+		if len(s) > 40 {
+			s = s[:40] + "..."
+		}
+		return fmt.Sprintf("Text(%q)", s), ""
+	}
+	if html.IsDynamic(e) {
+		return "Dynamic", "dynamic"
+	}
+	return strings.TrimPrefix(fmt.Sprintf("%T", e), "*html."), ""
+}
+
+// routeDetail returns extra information about the Route.
+func routeDetail(r Route) string {
+	switch r.Kind {
+	case HTTPRoute:
+		return fmt.Sprintf("%T", r.Handler)
+	case FSRoute:
+		return fmt.Sprintf("%T", r.FS)
+	case FilesRoute:
+		return fmt.Sprintf("%s %v", r.Dir, r.Exts)
+	}
+	return ""
+}
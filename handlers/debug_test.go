@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johnsiilver/webgear/html"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestDebugPage(t *testing.T) {
+	doc := &html.Doc{
+		Head: &html.Head{
+			Elements: []html.Element{
+				&html.Title{TagValue: html.TextElement("index")},
+			},
+		},
+		Body: &html.Body{
+			Elements: []html.Element{
+				&html.Div{
+					GlobalAttrs: html.GlobalAttrs{ID: "content"},
+					Events:      (&html.Events{}).AddScript(html.OnClick, "clicked"),
+					Elements: []html.Element{
+						html.Dynamic(func(pipe html.Pipeline) []html.Element { return nil }),
+					},
+				},
+			},
+		},
+	}
+
+	m := New(DoNotCompress(), DebugPage())
+	m.MustHandle("/", doc)
+	m.ServeFilesFrom(t.TempDir(), "/files/", []string{".css"})
+
+	want := []Route{
+		{Pattern: debugPattern, Kind: HTTPRoute},
+		{Pattern: "/", Kind: DocRoute},
+		{Pattern: "/files/", Kind: FilesRoute},
+	}
+	got := []Route{}
+	for _, r := range m.Routes() {
+		got = append(got, Route{Pattern: r.Pattern, Kind: r.Kind})
+	}
+	if diff := pretty.Compare(want, got); diff != "" {
+		t.Errorf("TestDebugPage(Routes): -want/+got:\n%s", diff)
+	}
+
+	w := httptest.NewRecorder()
+	m.ServerMux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, debugPattern, nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("TestDebugPage: got status %d, want %d", w.Code, http.StatusOK)
+	}
+
+	page := w.Body.String()
+	for _, s := range []string{"/files/", "Div", "content", `onclick=&#34;clicked&#34;`, "Dynamic"} {
+		if !strings.Contains(page, s) {
+			t.Errorf("TestDebugPage: page did not contain %q", s)
+		}
+	}
+}
@@ -1,10 +1,9 @@
 package handlers
 
 import (
-	"log"
 	"net/http"
 	"os"
-	"path/filepath"
+	"path"
 	"strings"
 )
 
@@ -13,18 +12,17 @@ import (
 // remove files and directories that start with a period from its output.
 type dotFileHidingFile struct {
 	http.File
-	exts map[string]bool
+	name  string
+	rules fileRules
 }
 
 // Readdir is a wrapper around the Readdir method of the embedded File
-// that filters out all files that start with a period in their name.
+// that filters out all files that start with a period in their name or
+// that are not allowed by our rules.
 func (f dotFileHidingFile) Readdir(n int) (fis []os.FileInfo, err error) {
 	files, err := f.File.Readdir(n)
-	for _, file := range files { // Filters out the dot files
-		if strings.HasPrefix(file.Name(), ".") {
-			continue
-		}
-		if !f.exts[filepath.Ext(file.Name())] {
+	for _, file := range files { // Filters out the dot files and files we don't serve.
+		if _, blocked := f.rules.blocked(path.Join(f.name, file.Name())); blocked {
 			continue
 		}
 		fis = append(fis, file)
@@ -32,32 +30,27 @@ func (f dotFileHidingFile) Readdir(n int) (fis []os.FileInfo, err error) {
 	return
 }
 
-// fileSystem is an http.FileSystem that hides hidden "dot files" and only serves files with
-// certain extensions.
+// fileSystem is an http.FileSystem that hides hidden "dot files" and only serves files
+// allowed by its rules.
 type fileSystem struct {
 	http.FileSystem
-	exts map[string]bool
+	rules fileRules
 }
 
 // Open is a wrapper around the Open method of the embedded FileSystem
 // that serves a 403 permission error when name has a file or directory
-// with whose name starts with a period in its path.
+// with whose name starts with a period in its path or is not allowed by
+// our rules. Logging of these is done by fileHandler, which has access to the request.
 func (fs fileSystem) Open(name string) (http.File, error) {
-	if containsDotFile(name) { // If dot file, return 403 response
-		return nil, os.ErrPermission
-	}
-
-	if !fs.exts[filepath.Ext(name)] {
-		log.Printf("probe for non-allowed file extension: %s", filepath.Ext(name))
+	if _, blocked := fs.rules.blocked(name); blocked {
 		return nil, os.ErrPermission
 	}
 
 	file, err := fs.FileSystem.Open(name)
 	if err != nil {
-		log.Println(err)
 		return nil, err
 	}
-	return dotFileHidingFile{file, fs.exts}, err
+	return dotFileHidingFile{File: file, name: name, rules: fs.rules}, err
 }
 
 // containsDotFile reports whether name contains a path element starting with a period.
@@ -18,3 +18,14 @@ func (w gzipResponseWriter) WriteHeader(status int) {
 func (w gzipResponseWriter) Write(b []byte) (int, error) {
 	return w.Writer.Write(b)
 }
+
+// Flush implements http.Flusher so that handlers that stream, such as server-sent events, work when
+// the response is compressed. It writes any buffered compressed data before flushing the connection.
+func (w gzipResponseWriter) Flush() {
+	if f, ok := w.Writer.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
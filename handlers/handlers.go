@@ -19,18 +19,10 @@ Usage is as follows:
 	// Attach that object to /.
 	h.MustHandle("/", index)
 
-	// Serve the content using the http.Server.
-	server := &http.Server{
-		Addr:           fmt.Sprintf(":%d", *port),
-		Handler:        h.ServerMux(),
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+	// Serve the content until an interrupt or SIGTERM is received. See Serve() for TLS and other options.
+	if err := handlers.Serve(context.Background(), h, handlers.Addr(fmt.Sprintf(":%d", *port))); err != nil {
+		log.Fatal(err)
 	}
-
-	log.Printf("http server serving on :%d", *port)
-
-	log.Fatal(server.ListenAndServe())
 */
 package handlers
 
@@ -58,6 +50,16 @@ type Mux struct {
 	gzipFiles bool
 	debug     bool
 
+	filePolicy *FilePolicy
+	debugPage  bool
+	sitemap    *SitemapConfig
+	robots     *string
+
+	routesMu sync.Mutex
+	routes   []Route
+	// lazyGears are the lazy loaded Gears registered by handleLazy(), keyed by pattern. Protected by routesMu.
+	lazyGears map[string]lazyGear
+
 	gzPool sync.Pool
 }
 
@@ -101,6 +103,28 @@ func Debug() Option {
 	}
 }
 
+// StaticFilePolicy applies a FilePolicy to ServeFS(), ServeFilesWorkingDir() and ServeFilesFrom().
+// This will panic if the FilePolicy has an invalid glob or a Doc that fails Init().
+func StaticFilePolicy(p *FilePolicy) Option {
+	if p == nil {
+		panic("StaticFilePolicy() cannot be passed a nil *FilePolicy")
+	}
+	if err := p.validate(); err != nil {
+		panic(err)
+	}
+	return func(m *Mux) {
+		m.filePolicy = p
+	}
+}
+
+// DebugPage causes the Mux to serve a page at /debug/webgear that lists all registered routes and the element
+// tree of each *html.Doc. This should not be used on a public facing server.
+func DebugPage() Option {
+	return func(m *Mux) {
+		m.debugPage = true
+	}
+}
+
 // New creates a new instance of Mux.
 func New(options ...Option) *Mux {
 	m := &Mux{
@@ -118,6 +142,16 @@ func New(options ...Option) *Mux {
 		option(m)
 	}
 
+	if m.debugPage {
+		m.HTTPHandler(debugPattern, m.DebugHandler())
+	}
+	if m.sitemap != nil {
+		m.HTTPHandler("/sitemap.xml", http.HandlerFunc(m.sitemapHandler))
+	}
+	if m.robots != nil {
+		m.HTTPHandler("/robots.txt", http.HandlerFunc(m.robotsHandler))
+	}
+
 	return m
 }
 
@@ -131,11 +165,15 @@ func (m *Mux) ServerMux() http.Handler {
 }
 
 // Handle registers the doc for a given pattern. If a handler already exists for pattern, Handle panics.
-// All handles will be gzip compressed by default.
+// All handles will be gzip compressed by default. Any component.Gear in the doc that uses component.Lazy()
+// has the handler that serves its content registered.
 func (m *Mux) Handle(pattern string, doc *html.Doc) error {
 	if err := doc.Init(); err != nil {
 		return err
 	}
+	if err := m.handleLazy(doc); err != nil {
+		return err
+	}
 
 	m.mux.Handle(
 		pattern,
@@ -152,6 +190,7 @@ func (m *Mux) Handle(pattern string, doc *html.Doc) error {
 			},
 		),
 	)
+	m.addRoute(Route{Pattern: pattern, Kind: DocRoute, Doc: doc})
 	return nil
 }
 
@@ -167,19 +206,41 @@ func (m *Mux) MustHandle(pattern string, doc *html.Doc) *Mux {
 // HTTPHandler registers a standard http.Handler for the pattern on the http.ServeMux.
 func (m *Mux) HTTPHandler(pattern string, handler http.Handler) {
 	m.mux.Handle(pattern, handler)
+	m.addRoute(Route{Pattern: pattern, Kind: HTTPRoute, Handler: handler})
 }
 
 // ServeFS passes a fs.FS that is walked and servers out of a root of /static/. This is similar to
 // ServeFilesWorkingDir() except it serves up all files in the FS that can be walked. Generally this
 // if for embeded files. Cannot be used with ServeFilesWorkingDir().
+// If StaticFilePolicy() was passed to New(), the FilePolicy is applied.
 func (m *Mux) ServeFS(filesys fs.FS) {
-	m.mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.FS(filesys))))
+	if m.filePolicy == nil {
+		m.addRoute(Route{Pattern: "/static/", Kind: FSRoute, FS: filesys})
+		m.mux.Handle("/static/", http.StripPrefix("/static/", http.FileServer(http.FS(filesys))))
+		return
+	}
+
+	rules := newFileRules(nil, m.filePolicy)
+	m.addRoute(Route{Pattern: "/static/", Kind: FSRoute, FS: filesys, rules: &rules})
+
+	m.mux.Handle(
+		"/static/",
+		http.StripPrefix(
+			"/static/",
+			m.newFileHandler(
+				fileSystem{
+					http.FS(filesys),
+					rules,
+				},
+			),
+		),
+	)
 }
 
 // ServeFilesWorkingDir will serve all files with the following file extensions that are in the
 // working directory or in any directory lower in the tree. It will never serve ., .. or .go files.
 // These files are all served from pattern. All files are served out of the /static/ path.
-// Cannot be used with ServeFS().
+// Cannot be used with ServeFS(). If StaticFilePolicy() was passed to New(), the FilePolicy is also applied.
 func (m *Mux) ServeFilesWorkingDir(exts []string) {
 	wd, err := os.Getwd()
 	if err != nil {
@@ -191,14 +252,17 @@ func (m *Mux) ServeFilesWorkingDir(exts []string) {
 		allowed[v] = true
 	}
 
+	rules := newFileRules(allowed, m.filePolicy)
+	m.addRoute(Route{Pattern: "/static/", Kind: FilesRoute, Dir: wd, Exts: exts, rules: &rules})
+
 	m.mux.Handle(
 		"/static/",
 		http.StripPrefix(
 			"/static/",
-			http.FileServer(
+			m.newFileHandler(
 				fileSystem{
 					http.Dir(wd),
-					allowed,
+					rules,
 				},
 			),
 		),
@@ -214,6 +278,7 @@ func (m *Mux) ServeFilesWorkingDir(exts []string) {
 // Aka, if you do: ServeFilesFrom("/some_dir", "", []string{".img"}}) and
 // ServeFilesFrom("/another_dir", "", []string{".img"}}), where /some_dir and /another_dir both contain img/, this
 // will panic.
+// If StaticFilePolicy() was passed to New(), the FilePolicy is also applied.
 func (m *Mux) ServeFilesFrom(dir, root string, exts []string) {
 	if root == "" {
 		root = "/static/"
@@ -224,14 +289,17 @@ func (m *Mux) ServeFilesFrom(dir, root string, exts []string) {
 		allowed[v] = true
 	}
 
+	rules := newFileRules(allowed, m.filePolicy)
+	m.addRoute(Route{Pattern: root, Kind: FilesRoute, Dir: dir, Exts: exts, rules: &rules})
+
 	m.mux.Handle(
 		root,
 		http.StripPrefix(
 			root,
-			http.FileServer(
+			m.newFileHandler(
 				fileSystem{
 					http.Dir(dir),
-					allowed,
+					rules,
 				},
 			),
 		),
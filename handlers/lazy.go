@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// lazyGear is implemented by *component.Gear. A Gear that is lazy loaded returns the pattern and
+// http.Handler that serves its content, otherwise pattern is empty.
+type lazyGear interface {
+	Name() string
+	LazyHandler() (pattern string, h http.Handler)
+}
+
+// handleLazy registers the handlers for all lazy loaded Gears in the doc that have not been registered.
+// An error is returned if a different Gear with the same name was registered by another Doc.
+func (m *Mux) handleLazy(doc *html.Doc) error {
+	if doc.Body == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var gears []lazyGear
+	for walked := range html.Walker(ctx, doc.Body) {
+		if lg, ok := walked.Element.(lazyGear); ok {
+			gears = append(gears, lg)
+		}
+	}
+
+	// The check and the registration happen under one lock so that concurrent Handle() calls
+	// cannot both register a pattern.
+	m.routesMu.Lock()
+	defer m.routesMu.Unlock()
+
+	for _, lg := range gears {
+		pattern, _ := lg.LazyHandler()
+		if pattern == "" {
+			continue
+		}
+		if prev, ok := m.lazyGears[pattern]; ok && prev != lg {
+			return fmt.Errorf("two different Gears have the same name %q", lg.Name())
+		}
+	}
+
+	for _, lg := range gears {
+		pattern, h := lg.LazyHandler()
+		if pattern == "" || m.lazyGears[pattern] != nil || m.hasRoute(pattern) {
+			continue
+		}
+		if m.lazyGears == nil {
+			m.lazyGears = map[string]lazyGear{}
+		}
+		m.lazyGears[pattern] = lg
+		m.mux.Handle(pattern, h)
+		m.routes = append(m.routes, Route{Pattern: pattern, Kind: HTTPRoute, Handler: h})
+	}
+	return nil
+}
+
+// hasRoute returns true if a route with pattern has been registered. m.routesMu must be held.
+func (m *Mux) hasRoute(pattern string) bool {
+	for _, r := range m.routes {
+		if r.Pattern == pattern {
+			return true
+		}
+	}
+	return false
+}
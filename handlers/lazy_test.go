@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/johnsiilver/webgear/component"
+	"github.com/johnsiilver/webgear/html"
+)
+
+func TestHandleLazy(t *testing.T) {
+	gear, err := component.New("my-lazy", &html.Doc{Body: &html.Body{}}, component.Lazy())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newDoc := func() *html.Doc {
+		return &html.Doc{
+			Head: &html.Head{Elements: []html.Element{&html.Title{TagValue: html.TextElement("title")}}},
+			Body: &html.Body{Elements: []html.Element{gear, &html.Component{Gear: gear}}},
+		}
+	}
+
+	m := New(DoNotCompress())
+	// The Gear is on two pages, but is only registered once.
+	m.MustHandle("/", newDoc())
+	m.MustHandle("/other", newDoc())
+
+	w := httptest.NewRecorder()
+	m.ServerMux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, component.LazyPath+"my-lazy", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("TestHandleLazy: got status %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestHandleLazyErrors(t *testing.T) {
+	newDoc := func(gear *component.Gear) *html.Doc {
+		return &html.Doc{
+			Head: &html.Head{Elements: []html.Element{&html.Title{TagValue: html.TextElement("title")}}},
+			Body: &html.Body{Elements: []html.Element{gear, &html.Component{Gear: gear}}},
+		}
+	}
+	newGear := func(options ...component.Option) *component.Gear {
+		g, err := component.New("my-lazy", &html.Doc{Body: &html.Body{}}, append(options, component.Lazy())...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return g
+	}
+
+	m := New(DoNotCompress())
+	m.MustHandle("/", newDoc(newGear()))
+	if err := m.Handle("/other", newDoc(newGear())); err == nil {
+		t.Errorf("TestHandleLazyErrors(different Gear with the same name): got err == nil, want err != nil")
+	}
+
+	// Concurrent Handle() calls with the same Gear must only register it once, or http.ServeMux panics.
+	gear := newGear()
+	m = New(DoNotCompress())
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.MustHandle(fmt.Sprintf("/page%d", i), newDoc(gear))
+		}()
+	}
+	wg.Wait()
+
+	// DataFunc errors are logged, not sent to the client.
+	df := func(r *http.Request) (interface{}, error) {
+		return nil, fmt.Errorf("secret database error")
+	}
+	m = New(DoNotCompress())
+	m.MustHandle("/", newDoc(newGear(component.ApplyDataFunc(df))))
+
+	w := httptest.NewRecorder()
+	m.ServerMux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, component.LazyPath+"my-lazy", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("TestHandleLazyErrors: got status %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	if strings.Contains(w.Body.String(), "secret") {
+		t.Errorf("TestHandleLazyErrors: response contained the DataFunc's error: %s", w.Body.String())
+	}
+}
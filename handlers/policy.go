@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// BlockReason details why a request for a static file was refused.
+type BlockReason int
+
+const (
+	// UnknownBlock indicates the BlockReason was not set.
+	UnknownBlock BlockReason = 0
+	// DotFileBlock indicates the path had a file or directory that started with a period.
+	DotFileBlock BlockReason = 1
+	// NotAllowedBlock indicates the path did not match an allowed extension or FilePolicy.Allow glob.
+	NotAllowedBlock BlockReason = 2
+	// DenyBlock indicates the path matched a FilePolicy.Deny glob.
+	DenyBlock BlockReason = 3
+)
+
+func (b BlockReason) String() string {
+	switch b {
+	case DotFileBlock:
+		return "dot file"
+	case NotAllowedBlock:
+		return "not allowed"
+	case DenyBlock:
+		return "denied"
+	}
+	return "unknown"
+}
+
+// Probe describes a request for a static file that was blocked.
+type Probe struct {
+	// Req is the request that was blocked.
+	Req *http.Request
+	// Path is the path of the file that was requested, relative to the root it is served from.
+	Path string
+	// Reason is why the request was blocked.
+	Reason BlockReason
+}
+
+// CacheRule sets the Cache-Control header for files matching Glob.
+type CacheRule struct {
+	// Glob is a pattern in path.Match() syntax. See FilePolicy for how it is matched.
+	Glob string
+	// Value is the value of the Cache-Control header, such as "public, max-age=86400".
+	Value string
+}
+
+// FilePolicy provides control over what the static file handlers (ServeFS(), ServeFilesWorkingDir() and
+// ServeFilesFrom()) will serve and how they serve it.
+//
+// All globs use path.Match() syntax. A glob without a "/" is matched against the file's name, so "*.png"
+// matches "/img/logo.png". A glob with a "/" is matched against the full path relative to the served
+// directory, so "/private/*" matches "/private/key.pem".
+//
+// A file is served if it does not contain a dot file, does not match a Deny glob and either matches one of the
+// extensions passed to the serving method or an Allow glob. ServeFS() with no Allow globs serves all files.
+type FilePolicy struct {
+	// Allow is a list of globs that are served in addition to any extensions passed to the serving method.
+	Allow []string
+	// Deny is a list of globs that are never served. Deny takes precedence over Allow.
+	Deny []string
+
+	// CacheControl is a list of rules to set the Cache-Control header on a served file. The first rule
+	// that matches is used. This is ignored if the DoNotCache() option was passed to New().
+	CacheControl []CacheRule
+
+	// Forbidden is rendered with a 403 status code when a request is blocked. If nil, a plain text
+	// message is returned.
+	Forbidden *html.Doc
+	// NotFound is rendered with a 404 status code when a file does not exist. If nil, a plain text
+	// message is returned.
+	NotFound *html.Doc
+
+	// OnBlocked is called each time a request is blocked. This can be used to feed a rate limiter or
+	// security logging. If nil, the probe is logged with the standard logger.
+	OnBlocked func(p Probe)
+}
+
+// validate checks that all globs in the FilePolicy are valid and initializes the Docs.
+func (f *FilePolicy) validate() error {
+	globs := append(append([]string{}, f.Allow...), f.Deny...)
+	for _, rule := range f.CacheControl {
+		globs = append(globs, rule.Glob)
+	}
+	for _, g := range globs {
+		if _, err := path.Match(g, ""); err != nil {
+			return fmt.Errorf("FilePolicy has bad glob %q: %w", g, err)
+		}
+	}
+
+	for _, doc := range []*html.Doc{f.Forbidden, f.NotFound} {
+		if doc == nil {
+			continue
+		}
+		if err := doc.Init(); err != nil {
+			return fmt.Errorf("FilePolicy had Doc that failed Init(): %w", err)
+		}
+	}
+	return nil
+}
+
+// cacheControl returns the Cache-Control value for name. If none matched, this is the empty string.
+func (f *FilePolicy) cacheControl(name string) string {
+	for _, rule := range f.CacheControl {
+		if globMatch(rule.Glob, name) {
+			return rule.Value
+		}
+	}
+	return ""
+}
+
+// fileRules decides if a file can be served.
+type fileRules struct {
+	// exts are the extensions that may be served. If nil, all extensions are allowed unless
+	// allow has entries.
+	exts  map[string]bool
+	allow []string
+	deny  []string
+}
+
+func newFileRules(exts map[string]bool, policy *FilePolicy) fileRules {
+	r := fileRules{exts: exts}
+	if policy != nil {
+		r.allow = policy.Allow
+		r.deny = policy.Deny
+	}
+	return r
+}
+
+// blocked reports if name should not be served and why.
+func (f fileRules) blocked(name string) (BlockReason, bool) {
+	if containsDotFile(name) {
+		return DotFileBlock, true
+	}
+	for _, g := range f.deny {
+		if globMatch(g, name) {
+			return DenyBlock, true
+		}
+	}
+
+	if f.exts == nil && len(f.allow) == 0 {
+		return UnknownBlock, false
+	}
+	if f.exts[path.Ext(name)] {
+		return UnknownBlock, false
+	}
+	for _, g := range f.allow {
+		if globMatch(g, name) {
+			return UnknownBlock, false
+		}
+	}
+	return NotAllowedBlock, true
+}
+
+// globMatch matches the glob against the base name of name, unless the glob contains a "/".
+// In that case it is matched against name.
+func globMatch(glob, name string) bool {
+	target := path.Base(name)
+	if strings.Contains(glob, "/") {
+		target = name
+	}
+	ok, _ := path.Match(glob, target)
+	return ok
+}
+
+// fileHandler enforces a FilePolicy before handing a request to an http.FileServer.
+type fileHandler struct {
+	fs      fileSystem
+	policy  *FilePolicy
+	caching bool
+	next    http.Handler
+}
+
+func (m *Mux) newFileHandler(fs fileSystem) fileHandler {
+	return fileHandler{
+		fs:      fs,
+		policy:  m.filePolicy,
+		caching: m.caching,
+		next:    http.FileServer(fs),
+	}
+}
+
+// ServeHTTP implements http.Handler.ServeHTTP.
+func (f fileHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	name := path.Clean("/" + r.URL.Path)
+
+	if reason, blocked := f.fs.rules.blocked(name); blocked {
+		f.blocked(w, r, Probe{Req: r, Path: name, Reason: reason})
+		return
+	}
+
+	if f.policy != nil {
+		if f.policy.NotFound != nil {
+			file, err := f.fs.FileSystem.Open(name)
+			if err != nil {
+				if os.IsNotExist(err) {
+					f.renderDoc(w, r, http.StatusNotFound, f.policy.NotFound)
+					return
+				}
+			} else {
+				file.Close()
+			}
+		}
+		if f.caching {
+			if v := f.policy.cacheControl(name); v != "" {
+				w.Header().Set("Cache-Control", v)
+			}
+		}
+	}
+
+	f.next.ServeHTTP(w, r)
+}
+
+func (f fileHandler) blocked(w http.ResponseWriter, r *http.Request, p Probe) {
+	if f.policy != nil && f.policy.OnBlocked != nil {
+		f.policy.OnBlocked(p)
+	} else {
+		log.Printf("probe for blocked file(%s) from %s: %s", p.Path, r.RemoteAddr, p.Reason)
+	}
+
+	if f.policy != nil && f.policy.Forbidden != nil {
+		f.renderDoc(w, r, http.StatusForbidden, f.policy.Forbidden)
+		return
+	}
+	http.Error(w, "403 Forbidden", http.StatusForbidden)
+}
+
+func (f fileHandler) renderDoc(w http.ResponseWriter, r *http.Request, code int, doc *html.Doc) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(code)
+	if err := doc.Execute(r.Context(), w, r); err != nil {
+		log.Printf("problem rendering %d page: %s", code, err)
+	}
+}
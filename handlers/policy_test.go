@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+func TestFilePolicy(t *testing.T) {
+	dir := t.TempDir()
+	files := []string{"index.css", "logo.png", "notes.txt", "private/key.css", ".git/config"}
+	for _, f := range files {
+		p := filepath.Join(dir, filepath.FromSlash(f))
+		if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(f), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var probes []Probe
+	policy := &FilePolicy{
+		Allow:        []string{"*.png"},
+		Deny:         []string{"/private/*"},
+		CacheControl: []CacheRule{{Glob: "*.png", Value: "public, max-age=60"}},
+		NotFound: &html.Doc{
+			Head: &html.Head{Elements: []html.Element{&html.Title{TagValue: html.TextElement("missing")}}},
+			Body: &html.Body{Elements: []html.Element{html.TextElement("nothing here")}},
+		},
+		OnBlocked: func(p Probe) {
+			probes = append(probes, p)
+		},
+	}
+
+	m := New(DoNotCompress(), StaticFilePolicy(policy))
+	m.ServeFilesFrom(dir, "", []string{".css"})
+
+	tests := []struct {
+		desc      string
+		path      string
+		wantCode  int
+		wantBody  string
+		wantCache string
+		wantProbe BlockReason
+	}{
+		{desc: "Allowed by extension", path: "/static/index.css", wantCode: 200, wantBody: "index.css"},
+		{desc: "Allowed by glob", path: "/static/logo.png", wantCode: 200, wantBody: "logo.png", wantCache: "public, max-age=60"},
+		{desc: "Not allowed", path: "/static/notes.txt", wantCode: 403, wantProbe: NotAllowedBlock},
+		{desc: "Denied", path: "/static/private/key.css", wantCode: 403, wantProbe: DenyBlock},
+		{desc: "Dot file", path: "/static/.git/config", wantCode: 403, wantProbe: DotFileBlock},
+		{desc: "Not found", path: "/static/missing.css", wantCode: 404, wantBody: "nothing here"},
+	}
+
+	for _, test := range tests {
+		probes = nil
+
+		w := httptest.NewRecorder()
+		m.ServerMux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+
+		if w.Code != test.wantCode {
+			t.Errorf("TestFilePolicy(%s): got status %d, want %d", test.desc, w.Code, test.wantCode)
+			continue
+		}
+		if !strings.Contains(w.Body.String(), test.wantBody) {
+			t.Errorf("TestFilePolicy(%s): got body %q, want it to contain %q", test.desc, w.Body.String(), test.wantBody)
+		}
+		if got := w.Header().Get("Cache-Control"); got != test.wantCache {
+			t.Errorf("TestFilePolicy(%s): got Cache-Control %q, want %q", test.desc, got, test.wantCache)
+		}
+
+		switch {
+		case test.wantProbe == UnknownBlock && len(probes) != 0:
+			t.Errorf("TestFilePolicy(%s): got probes %v, want none", test.desc, probes)
+		case test.wantProbe != UnknownBlock && (len(probes) != 1 || probes[0].Reason != test.wantProbe):
+			t.Errorf("TestFilePolicy(%s): got probes %v, want 1 with reason %s", test.desc, probes, test.wantProbe)
+		}
+	}
+}
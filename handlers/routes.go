@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+// RouteKind details what type of content was registered for a Route.
+type RouteKind int
+
+const (
+	// UnknownRoute indicates the RouteKind was not set.
+	UnknownRoute RouteKind = 0
+	// DocRoute was registered with Handle() or MustHandle().
+	DocRoute RouteKind = 1
+	// HTTPRoute was registered with HTTPHandler().
+	HTTPRoute RouteKind = 2
+	// FSRoute was registered with ServeFS().
+	FSRoute RouteKind = 3
+	// FilesRoute was registered with ServeFilesWorkingDir() or ServeFilesFrom().
+	FilesRoute RouteKind = 4
+)
+
+func (r RouteKind) String() string {
+	switch r {
+	case DocRoute:
+		return "Doc"
+	case HTTPRoute:
+		return "http.Handler"
+	case FSRoute:
+		return "fs.FS"
+	case FilesRoute:
+		return "Files"
+	}
+	return "Unknown"
+}
+
+// Route describes a pattern registered on the Mux.
+type Route struct {
+	// Pattern is the pattern passed to the http.ServeMux.
+	Pattern string
+	// Kind is the type of content served at Pattern.
+	Kind RouteKind
+
+	// Doc is the *html.Doc being served. Only set if Kind == DocRoute.
+	Doc *html.Doc
+	// Handler is the http.Handler being served. Only set if Kind == HTTPRoute.
+	Handler http.Handler
+	// FS is the filesystem being served. Only set if Kind == FSRoute.
+	FS fs.FS
+	// Dir is the directory files are served from. Only set if Kind == FilesRoute.
+	Dir string
+	// Exts are the file extensions that are served. Only set if Kind == FilesRoute.
+	Exts []string
+
+	// rules decide which files an FSRoute or FilesRoute serves. If nil, all files are served.
+	rules *fileRules
+}
+
+// Serves reports if a request for the file at name, a slash separated path relative to FS or Dir, is
+// answered instead of blocked because of a dot file, Exts or a FilePolicy. This is always true for
+// Routes that are not an FSRoute or FilesRoute.
+func (r Route) Serves(name string) bool {
+	if r.rules == nil {
+		return true
+	}
+	_, blocked := r.rules.blocked(path.Clean("/" + name))
+	return !blocked
+}
+
+func (m *Mux) addRoute(r Route) {
+	m.routesMu.Lock()
+	defer m.routesMu.Unlock()
+
+	m.routes = append(m.routes, r)
+}
+
+// Routes returns all Routes registered on the Mux in the order they were registered.
+func (m *Mux) Routes() []Route {
+	m.routesMu.Lock()
+	defer m.routesMu.Unlock()
+
+	out := make([]Route, len(m.routes))
+	copy(out, m.routes)
+	return out
+}
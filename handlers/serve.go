@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ServeOption is an optional argument to Serve().
+type ServeOption func(s *server) error
+
+// Addr is the address to serve on, such as ":8080". If not provided, ":8080" is used or ":8443" if serving TLS.
+func Addr(addr string) ServeOption {
+	return func(s *server) error {
+		s.addr = addr
+		return nil
+	}
+}
+
+// Listener causes Serve() to use the passed net.Listener instead of listening on Addr(). This is useful
+// for tests or when you need to know the port after listening on port 0.
+func Listener(l net.Listener) ServeOption {
+	return func(s *server) error {
+		if l == nil {
+			return fmt.Errorf("Listener() cannot be passed a nil net.Listener")
+		}
+		s.listener = l
+		return nil
+	}
+}
+
+// TLSFiles causes Serve() to serve HTTPS using the certificate and key in the PEM encoded files. The server will
+// require TLS 1.2 or higher and will negotiate HTTP/2 with clients that support it.
+func TLSFiles(certFile, keyFile string) ServeOption {
+	return func(s *server) error {
+		if _, err := tls.LoadX509KeyPair(certFile, keyFile); err != nil {
+			return fmt.Errorf("TLSFiles() could not load certificate: %w", err)
+		}
+		s.certFile, s.keyFile = certFile, keyFile
+		return nil
+	}
+}
+
+// DisableHTTP2 prevents the server from negotiating HTTP/2 when serving TLS.
+func DisableHTTP2() ServeOption {
+	return func(s *server) error {
+		s.noHTTP2 = true
+		return nil
+	}
+}
+
+// RedirectHTTP starts a second listener at addr, such as ":80", that redirects all requests to the HTTPS server.
+// Must be used with TLSFiles().
+func RedirectHTTP(addr string) ServeOption {
+	return func(s *server) error {
+		s.redirectAddr = addr
+		return nil
+	}
+}
+
+// HealthChecks adds endpoints at healthPath and readyPath, such as "/healthz" and "/readyz". healthPath always
+// returns 200 while the server is running. readyPath returns 200 while the server is accepting requests and 503
+// once shutdown has started. Either can be the empty string to not serve that endpoint. Use DrainDelay() so
+// that load balancers see the 503 before the server stops accepting connections.
+func HealthChecks(healthPath, readyPath string) ServeOption {
+	return func(s *server) error {
+		s.healthPath, s.readyPath = healthPath, readyPath
+		return nil
+	}
+}
+
+// Timeouts sets the read and write timeouts of the server. The default for both is 10 seconds.
+func Timeouts(read, write time.Duration) ServeOption {
+	return func(s *server) error {
+		s.readTimeout, s.writeTimeout = read, write
+		return nil
+	}
+}
+
+// ShutdownTimeout is how long to wait for requests to finish after shutdown has started. The default is 30 seconds.
+func ShutdownTimeout(d time.Duration) ServeOption {
+	return func(s *server) error {
+		s.shutdownTimeout = d
+		return nil
+	}
+}
+
+// DrainDelay is how long to keep serving after shutdown has started and before the server stops accepting
+// connections. During this time the readyPath of HealthChecks() returns 503, which gives load balancers time to
+// stop sending traffic. This should be longer than the interval of your readiness probe. The default is 0.
+func DrainDelay(d time.Duration) ServeOption {
+	return func(s *server) error {
+		if d < 0 {
+			return fmt.Errorf("DrainDelay() cannot be passed a negative duration")
+		}
+		s.drainDelay = d
+		return nil
+	}
+}
+
+// Signals are the signals that cause a graceful shutdown. The default is os.Interrupt and syscall.SIGTERM.
+// Passing no signals means that only cancelling the Context passed to Serve() will cause a shutdown.
+func Signals(sigs ...os.Signal) ServeOption {
+	return func(s *server) error {
+		s.signals = sigs
+		return nil
+	}
+}
+
+type server struct {
+	addr     string
+	listener net.Listener
+
+	certFile, keyFile string
+	noHTTP2           bool
+	redirectAddr      string
+
+	healthPath, readyPath string
+	ready                 int32
+
+	readTimeout, writeTimeout time.Duration
+	shutdownTimeout           time.Duration
+	drainDelay                time.Duration
+	signals                   []os.Signal
+}
+
+func (s *server) tls() bool {
+	return s.certFile != ""
+}
+
+// handler wraps next with our health check endpoints.
+func (s *server) handler(next http.Handler) http.Handler {
+	if s.healthPath == "" && s.readyPath == "" {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case s.healthPath != "" && r.URL.Path == s.healthPath:
+			w.Write([]byte("ok"))
+			return
+		case s.readyPath != "" && r.URL.Path == s.readyPath:
+			if atomic.LoadInt32(&s.ready) == 0 {
+				http.Error(w, "not ready", http.StatusServiceUnavailable)
+				return
+			}
+			w.Write([]byte("ok"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// redirectHandler redirects all requests to the https version of the URL.
+func (s *server) redirectHandler(httpsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(r.Host); err == nil {
+			host = h
+		}
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+}
+
+// Serve serves the Mux until ctx is cancelled or one of the shutdown signals is received, at which point
+// it gracefully shuts down. An error is returned if the server could not start or did not shut down cleanly.
+// A graceful shutdown returns nil.
+func Serve(ctx context.Context, m *Mux, options ...ServeOption) error {
+	s := &server{
+		readTimeout:     10 * time.Second,
+		writeTimeout:    10 * time.Second,
+		shutdownTimeout: 30 * time.Second,
+		signals:         []os.Signal{os.Interrupt, syscall.SIGTERM},
+	}
+	for _, o := range options {
+		if err := o(s); err != nil {
+			return err
+		}
+	}
+
+	if s.redirectAddr != "" && !s.tls() {
+		return fmt.Errorf("RedirectHTTP() cannot be used without TLSFiles()")
+	}
+	if s.addr == "" {
+		s.addr = ":8080"
+		if s.tls() {
+			s.addr = ":8443"
+		}
+	}
+
+	if len(s.signals) > 0 {
+		var stop context.CancelFunc
+		ctx, stop = signal.NotifyContext(ctx, s.signals...)
+		defer stop()
+	}
+
+	l := s.listener
+	if l == nil {
+		var err error
+		l, err = net.Listen("tcp", s.addr)
+		if err != nil {
+			return err
+		}
+	}
+
+	srv := &http.Server{
+		Handler:        s.handler(m.ServerMux()),
+		ReadTimeout:    s.readTimeout,
+		WriteTimeout:   s.writeTimeout,
+		MaxHeaderBytes: 1 << 20,
+	}
+	servers := []*http.Server{srv}
+
+	errCh := make(chan error, 2)
+	if s.tls() {
+		srv.TLSConfig = &tls.Config{
+			MinVersion:       tls.VersionTLS12,
+			CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		}
+		if s.noHTTP2 {
+			srv.TLSNextProto = map[string]func(*http.Server, *tls.Conn, http.Handler){}
+		}
+		log.Printf("https server serving on %s", l.Addr())
+		go func() { errCh <- srv.ServeTLS(l, s.certFile, s.keyFile) }()
+	} else {
+		log.Printf("http server serving on %s", l.Addr())
+		go func() { errCh <- srv.Serve(l) }()
+	}
+
+	if s.redirectAddr != "" {
+		redirect := &http.Server{
+			Addr:           s.redirectAddr,
+			Handler:        s.redirectHandler(l.Addr().String()),
+			ReadTimeout:    s.readTimeout,
+			WriteTimeout:   s.writeTimeout,
+			MaxHeaderBytes: 1 << 20,
+		}
+		servers = append(servers, redirect)
+		log.Printf("http redirect server serving on %s", s.redirectAddr)
+		go func() { errCh <- redirect.ListenAndServe() }()
+	}
+
+	atomic.StoreInt32(&s.ready, 1)
+
+	var serveErr error
+	select {
+	case <-ctx.Done():
+	case serveErr = <-errCh:
+	}
+	atomic.StoreInt32(&s.ready, 0)
+
+	// Keep serving while load balancers notice we are not ready, unless the server has already failed.
+	if serveErr == nil && s.drainDelay > 0 {
+		log.Printf("server draining for %s before shutdown", s.drainDelay)
+		time.Sleep(s.drainDelay)
+	}
+
+	sctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	for _, server := range servers {
+		if err := server.Shutdown(sctx); err != nil && serveErr == nil {
+			serveErr = fmt.Errorf("server did not shutdown cleanly: %w", err)
+		}
+	}
+
+	if errors.Is(serveErr, http.ErrServerClosed) {
+		return nil
+	}
+	return serveErr
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+func TestServe(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := New()
+	m.MustHandle(
+		"/",
+		&html.Doc{
+			Head: &html.Head{Elements: []html.Element{&html.Title{TagValue: html.TextElement("title")}}},
+			Body: &html.Body{Elements: []html.Element{html.TextElement("hello")}},
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, m, Listener(l), HealthChecks("/healthz", "/readyz"), Signals())
+	}()
+
+	base := "http://" + l.Addr().String()
+	tests := []struct {
+		desc string
+		path string
+		want int
+	}{
+		{desc: "Doc", path: "/", want: http.StatusOK},
+		{desc: "Health", path: "/healthz", want: http.StatusOK},
+		{desc: "Ready", path: "/readyz", want: http.StatusOK},
+	}
+
+	for _, test := range tests {
+		var resp *http.Response
+		// The server may not be ready for the first request.
+		for i := 0; i < 50; i++ {
+			resp, err = http.Get(base + test.path)
+			if err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		if err != nil {
+			t.Fatalf("TestServe(%s): got err == %s, want err == nil", test.desc, err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != test.want {
+			t.Errorf("TestServe(%s): got status %d, want %d", test.desc, resp.StatusCode, test.want)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("TestServe: Serve() returned err == %s, want err == nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("TestServe: Serve() did not return after Context was cancelled")
+	}
+}
+
+func TestServeDrainDelay(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- Serve(ctx, New(), Listener(l), HealthChecks("/healthz", "/readyz"), DrainDelay(time.Second), Signals())
+	}()
+
+	get := func(path string) int {
+		resp, err := http.Get("http://" + l.Addr().String() + path)
+		if err != nil {
+			t.Fatalf("TestServeDrainDelay(%s): got err == %s, want err == nil", path, err)
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// Wait for the server to be ready.
+	for i := 0; i < 50; i++ {
+		resp, err := http.Get("http://" + l.Addr().String() + "/readyz")
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	cancel()
+	time.Sleep(100 * time.Millisecond)
+
+	if got := get("/readyz"); got != http.StatusServiceUnavailable {
+		t.Errorf("TestServeDrainDelay: readyz got status %d, want %d", got, http.StatusServiceUnavailable)
+	}
+	if got := get("/healthz"); got != http.StatusOK {
+		t.Errorf("TestServeDrainDelay: healthz got status %d, want %d", got, http.StatusOK)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("TestServeDrainDelay: Serve() returned err == %s, want err == nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("TestServeDrainDelay: Serve() did not return after the DrainDelay")
+	}
+}
+
+func TestServeRedirectRequiresTLS(t *testing.T) {
+	err := Serve(context.Background(), New(), RedirectHTTP(":0"), Signals())
+	if err == nil {
+		t.Errorf("TestServeRedirectRequiresTLS: got err == nil, want err != nil")
+	}
+}
+
+func TestRedirectHandler(t *testing.T) {
+	tests := []struct {
+		desc      string
+		httpsAddr string
+		url       string
+		want      string
+	}{
+		{desc: "Standard port", httpsAddr: "[::]:443", url: "http://example.com/a?b=c", want: "https://example.com/a?b=c"},
+		{desc: "Non-standard port", httpsAddr: "[::]:8443", url: "http://example.com:8080/a", want: "https://example.com:8443/a"},
+	}
+
+	s := &server{}
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		s.redirectHandler(test.httpsAddr).ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.url, nil))
+
+		if got := w.Header().Get("Location"); got != test.want {
+			t.Errorf("TestRedirectHandler(%s): got %q, want %q", test.desc, got, test.want)
+		}
+	}
+}
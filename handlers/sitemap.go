@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ChangeFreq is how frequently a page is likely to change. This is a hint to search engines.
+type ChangeFreq string
+
+const (
+	Always  ChangeFreq = "always"
+	Hourly  ChangeFreq = "hourly"
+	Daily   ChangeFreq = "daily"
+	Weekly  ChangeFreq = "weekly"
+	Monthly ChangeFreq = "monthly"
+	Yearly  ChangeFreq = "yearly"
+	Never   ChangeFreq = "never"
+)
+
+// ExpandFunc returns the URL paths that a pattern serves, such as "/blog/my-first-post" for "/blog/". This is
+// used for patterns that serve more than a single page.
+type ExpandFunc func(pattern string) ([]string, error)
+
+// SitemapRoute provides sitemap settings for a Doc route.
+type SitemapRoute struct {
+	// ChangeFreq is how often the page changes. If not set, it is not output.
+	ChangeFreq ChangeFreq
+	// Priority is the priority of this page relative to others on the site, 0.0 to 1.0. If 0, it is not output.
+	Priority float64
+	// LastMod is when the page was last modified. If zero, it is not output.
+	LastMod time.Time
+	// Expand, if set, provides the paths to list for the route instead of the pattern. Patterns with wildcards
+	// like "/users/{id}" are only listed if this is set.
+	Expand ExpandFunc
+	// Exclude causes the route to not be listed.
+	Exclude bool
+}
+
+// SitemapConfig is the configuration for the Sitemap() option.
+type SitemapConfig struct {
+	// BaseURL is the scheme and host to put in front of every path, such as "https://example.com". This must
+	// be set. It is not taken from the request, as the Host header is set by the client and the output may
+	// be cached by StaticMode().
+	BaseURL *url.URL
+	// Routes are settings for specific patterns that were passed to Handle(). Any pattern not listed uses Default.
+	Routes map[string]SitemapRoute
+	// Default are the settings for any route not in Routes.
+	Default SitemapRoute
+}
+
+func (s SitemapConfig) validate() error {
+	switch {
+	case s.BaseURL == nil:
+		return fmt.Errorf("SitemapConfig.BaseURL must be set")
+	case s.BaseURL.Scheme != "http" && s.BaseURL.Scheme != "https":
+		return fmt.Errorf("SitemapConfig.BaseURL(%s) must have a scheme of http or https", s.BaseURL)
+	case s.BaseURL.Host == "":
+		return fmt.Errorf("SitemapConfig.BaseURL(%s) must have a host", s.BaseURL)
+	}
+
+	routes := []SitemapRoute{s.Default}
+	for _, r := range s.Routes {
+		routes = append(routes, r)
+	}
+	for _, r := range routes {
+		if r.Priority < 0 || r.Priority > 1 {
+			return fmt.Errorf("SitemapRoute.Priority must be between 0.0 and 1.0, was %v", r.Priority)
+		}
+		switch r.ChangeFreq {
+		case "", Always, Hourly, Daily, Weekly, Monthly, Yearly, Never:
+		default:
+			return fmt.Errorf("SitemapRoute.ChangeFreq %q is not valid", r.ChangeFreq)
+		}
+	}
+	return nil
+}
+
+// Sitemap causes the Mux to serve /sitemap.xml, which lists every route registered with Handle() or
+// MustHandle(). Patterns that include a host are not listed. This will panic if the SitemapConfig has
+// invalid values, such as not having a BaseURL.
+func Sitemap(conf SitemapConfig) Option {
+	if err := conf.validate(); err != nil {
+		panic(err)
+	}
+	return func(m *Mux) {
+		m.sitemap = &conf
+	}
+}
+
+// RobotsTxt causes the Mux to serve the content passed at /robots.txt. If Sitemap() is also used and the
+// content does not have a "Sitemap:" line, one pointing to /sitemap.xml is added.
+func RobotsTxt(content string) Option {
+	return func(m *Mux) {
+		m.robots = &content
+	}
+}
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc        string     `xml:"loc"`
+	LastMod    string     `xml:"lastmod,omitempty"`
+	ChangeFreq ChangeFreq `xml:"changefreq,omitempty"`
+	Priority   string     `xml:"priority,omitempty"`
+}
+
+// baseURL returns the scheme and host that paths are relative to.
+func (s *SitemapConfig) baseURL() string {
+	return strings.TrimSuffix(s.BaseURL.String(), "/")
+}
+
+// sitemapURLs returns an entry for every Doc route in the Mux.
+func (m *Mux) sitemapURLs(base string) ([]sitemapURL, error) {
+	urls := []sitemapURL{}
+	seen := map[string]bool{}
+
+	for _, route := range m.Routes() {
+		if route.Kind != DocRoute {
+			continue
+		}
+		// Go 1.22 style patterns can start with a method, such as "GET /".
+		pattern := route.Pattern
+		if i := strings.Index(pattern, " "); i != -1 {
+			pattern = strings.TrimSpace(pattern[i+1:])
+		}
+		if !strings.HasPrefix(pattern, "/") {
+			continue
+		}
+
+		conf, ok := m.sitemap.Routes[route.Pattern]
+		if !ok {
+			conf = m.sitemap.Default
+		}
+		if conf.Exclude {
+			continue
+		}
+
+		paths := []string{pattern}
+		switch {
+		case conf.Expand != nil:
+			var err error
+			paths, err = conf.Expand(route.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("sitemap could not expand pattern %q: %w", route.Pattern, err)
+			}
+		case strings.Contains(pattern, "{"):
+			continue
+		}
+
+		for _, p := range paths {
+			if seen[p] {
+				continue
+			}
+			seen[p] = true
+
+			u := sitemapURL{Loc: base + p, ChangeFreq: conf.ChangeFreq}
+			if !conf.LastMod.IsZero() {
+				u.LastMod = conf.LastMod.Format("2006-01-02")
+			}
+			if conf.Priority > 0 {
+				u.Priority = strconv.FormatFloat(conf.Priority, 'f', -1, 64)
+			}
+			urls = append(urls, u)
+		}
+	}
+	return urls, nil
+}
+
+func (m *Mux) sitemapHandler(w http.ResponseWriter, r *http.Request) {
+	urls, err := m.sitemapURLs(m.sitemap.baseURL())
+	if err != nil {
+		log.Println(err)
+		http.Error(w, "could not generate sitemap", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	io.WriteString(w, xml.Header)
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(urlSet{XMLNS: sitemapNS, URLs: urls}); err != nil {
+		log.Printf("problem writing sitemap: %s", err)
+	}
+}
+
+func (m *Mux) robotsHandler(w http.ResponseWriter, r *http.Request) {
+	content := *m.robots
+	if m.sitemap != nil && !strings.Contains(strings.ToLower(content), "sitemap:") {
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += "Sitemap: " + m.sitemap.baseURL() + "/sitemap.xml\n"
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, content)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/johnsiilver/webgear/html"
+)
+
+func TestSitemap(t *testing.T) {
+	newDoc := func() *html.Doc {
+		return &html.Doc{
+			Head: &html.Head{Elements: []html.Element{&html.Title{TagValue: html.TextElement("title")}}},
+			Body: &html.Body{},
+		}
+	}
+
+	m := New(
+		DoNotCompress(),
+		Sitemap(
+			SitemapConfig{
+				BaseURL: html.URLParse("https://example.com/"),
+				Default: SitemapRoute{ChangeFreq: Weekly},
+				Routes: map[string]SitemapRoute{
+					"/blog/": {
+						Priority: 0.8,
+						Expand: func(pattern string) ([]string, error) {
+							return []string{pattern + "first", pattern + "second"}, nil
+						},
+					},
+					"/private": {Exclude: true},
+				},
+			},
+		),
+		RobotsTxt("User-agent: *\nDisallow: /private"),
+	)
+	m.MustHandle("/", newDoc())
+	m.MustHandle("/blog/", newDoc())
+	m.MustHandle("/private", newDoc())
+
+	tests := []struct {
+		desc    string
+		path    string
+		want    []string
+		notWant []string
+	}{
+		{
+			desc: "sitemap.xml",
+			path: "/sitemap.xml",
+			want: []string{
+				`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+				"<loc>https://example.com/</loc>",
+				"<changefreq>weekly</changefreq>",
+				"<loc>https://example.com/blog/first</loc>",
+				"<loc>https://example.com/blog/second</loc>",
+				"<priority>0.8</priority>",
+			},
+			notWant: []string{"/private", "sitemap.xml</loc>", "<loc>https://example.com/blog/</loc>"},
+		},
+		{
+			desc: "robots.txt",
+			path: "/robots.txt",
+			want: []string{"Disallow: /private\nSitemap: https://example.com/sitemap.xml\n"},
+		},
+	}
+
+	for _, test := range tests {
+		w := httptest.NewRecorder()
+		m.ServerMux().ServeHTTP(w, httptest.NewRequest(http.MethodGet, test.path, nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("TestSitemap(%s): got status %d, want %d", test.desc, w.Code, http.StatusOK)
+			continue
+		}
+		got := w.Body.String()
+		for _, s := range test.want {
+			if !strings.Contains(got, s) {
+				t.Errorf("TestSitemap(%s): output did not contain %q:\n%s", test.desc, s, got)
+			}
+		}
+		for _, s := range test.notWant {
+			if strings.Contains(got, s) {
+				t.Errorf("TestSitemap(%s): output contained %q:\n%s", test.desc, s, got)
+			}
+		}
+	}
+}
+
+func TestSitemapBaseURL(t *testing.T) {
+	tests := []struct {
+		desc    string
+		baseURL string
+	}{
+		{desc: "Not set"},
+		{desc: "No scheme", baseURL: "example.com"},
+		{desc: "Bad scheme", baseURL: "ftp://example.com"},
+		{desc: "No host", baseURL: "https:///path"},
+	}
+
+	for _, test := range tests {
+		conf := SitemapConfig{}
+		if test.baseURL != "" {
+			conf.BaseURL = html.URLParse(test.baseURL)
+		}
+		if err := conf.validate(); err == nil {
+			t.Errorf("TestSitemapBaseURL(%s): got err == nil, want err != nil", test.desc)
+		}
+	}
+}
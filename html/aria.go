@@ -0,0 +1,224 @@
+package html
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Role is the ARIA role of an element, which tells assistive technologies what the element is. Roles
+// should only be used when there isn't an element with the meaning built in, such as using a Button
+// instead of a Div with RoleButton. A Role can hold several roles separated by spaces, where later
+// roles are fallbacks for browsers that do not support earlier ones.
+// See https://www.w3.org/TR/wai-aria-1.2/#role_definitions .
+type Role string
+
+const (
+	RoleAlert            Role = "alert"
+	RoleAlertDialog      Role = "alertdialog"
+	RoleApplication      Role = "application"
+	RoleArticle          Role = "article"
+	RoleBanner           Role = "banner"
+	RoleBlockquote       Role = "blockquote"
+	RoleButton           Role = "button"
+	RoleCaption          Role = "caption"
+	RoleCell             Role = "cell"
+	RoleCheckbox         Role = "checkbox"
+	RoleCode             Role = "code"
+	RoleColumnHeader     Role = "columnheader"
+	RoleCombobox         Role = "combobox"
+	RoleComplementary    Role = "complementary"
+	RoleContentInfo      Role = "contentinfo"
+	RoleDefinition       Role = "definition"
+	RoleDeletion         Role = "deletion"
+	RoleDialog           Role = "dialog"
+	RoleDirectory        Role = "directory"
+	RoleDocument         Role = "document"
+	RoleEmphasis         Role = "emphasis"
+	RoleFeed             Role = "feed"
+	RoleFigure           Role = "figure"
+	RoleForm             Role = "form"
+	RoleGeneric          Role = "generic"
+	RoleGrid             Role = "grid"
+	RoleGridCell         Role = "gridcell"
+	RoleGroup            Role = "group"
+	RoleHeading          Role = "heading"
+	RoleImg              Role = "img"
+	RoleInsertion        Role = "insertion"
+	RoleLink             Role = "link"
+	RoleList             Role = "list"
+	RoleListbox          Role = "listbox"
+	RoleListItem         Role = "listitem"
+	RoleLog              Role = "log"
+	RoleMain             Role = "main"
+	RoleMarquee          Role = "marquee"
+	RoleMath             Role = "math"
+	RoleMenu             Role = "menu"
+	RoleMenubar          Role = "menubar"
+	RoleMenuItem         Role = "menuitem"
+	RoleMenuItemCheckbox Role = "menuitemcheckbox"
+	RoleMenuItemRadio    Role = "menuitemradio"
+	RoleMeter            Role = "meter"
+	RoleNavigation       Role = "navigation"
+	RoleNone             Role = "none"
+	RoleNote             Role = "note"
+	RoleOption           Role = "option"
+	RoleParagraph        Role = "paragraph"
+	RolePresentation     Role = "presentation"
+	RoleProgressbar      Role = "progressbar"
+	RoleRadio            Role = "radio"
+	RoleRadioGroup       Role = "radiogroup"
+	RoleRegion           Role = "region"
+	RoleRow              Role = "row"
+	RoleRowGroup         Role = "rowgroup"
+	RoleRowHeader        Role = "rowheader"
+	RoleScrollbar        Role = "scrollbar"
+	RoleSearch           Role = "search"
+	RoleSearchbox        Role = "searchbox"
+	RoleSeparator        Role = "separator"
+	RoleSlider           Role = "slider"
+	RoleSpinButton       Role = "spinbutton"
+	RoleStatus           Role = "status"
+	RoleStrong           Role = "strong"
+	RoleSubscript        Role = "subscript"
+	RoleSuperscript      Role = "superscript"
+	RoleSwitch           Role = "switch"
+	RoleTab              Role = "tab"
+	RoleTable            Role = "table"
+	RoleTablist          Role = "tablist"
+	RoleTabPanel         Role = "tabpanel"
+	RoleTerm             Role = "term"
+	RoleTextbox          Role = "textbox"
+	RoleTime             Role = "time"
+	RoleTimer            Role = "timer"
+	RoleToolbar          Role = "toolbar"
+	RoleTooltip          Role = "tooltip"
+	RoleTree             Role = "tree"
+	RoleTreeGrid         Role = "treegrid"
+	RoleTreeItem         Role = "treeitem"
+)
+
+// roles are the Role values that can be used. Abstract roles, such as "widget", are not included
+// because they cannot be used in content.
+var roles = map[Role]bool{
+	RoleAlert: true, RoleAlertDialog: true, RoleApplication: true, RoleArticle: true, RoleBanner: true,
+	RoleBlockquote: true, RoleButton: true, RoleCaption: true, RoleCell: true, RoleCheckbox: true, RoleCode: true,
+	RoleColumnHeader: true, RoleCombobox: true, RoleComplementary: true, RoleContentInfo: true, RoleDefinition: true,
+	RoleDeletion: true, RoleDialog: true, RoleDirectory: true, RoleDocument: true, RoleEmphasis: true, RoleFeed: true,
+	RoleFigure: true, RoleForm: true, RoleGeneric: true, RoleGrid: true, RoleGridCell: true, RoleGroup: true,
+	RoleHeading: true, RoleImg: true, RoleInsertion: true, RoleLink: true, RoleList: true, RoleListbox: true,
+	RoleListItem: true, RoleLog: true, RoleMain: true, RoleMarquee: true, RoleMath: true, RoleMenu: true,
+	RoleMenubar: true, RoleMenuItem: true, RoleMenuItemCheckbox: true, RoleMenuItemRadio: true, RoleMeter: true,
+	RoleNavigation: true, RoleNone: true, RoleNote: true, RoleOption: true, RoleParagraph: true,
+	RolePresentation: true, RoleProgressbar: true, RoleRadio: true, RoleRadioGroup: true, RoleRegion: true,
+	RoleRow: true, RoleRowGroup: true, RoleRowHeader: true, RoleScrollbar: true, RoleSearch: true,
+	RoleSearchbox: true, RoleSeparator: true, RoleSlider: true, RoleSpinButton: true, RoleStatus: true,
+	RoleStrong: true, RoleSubscript: true, RoleSuperscript: true, RoleSwitch: true, RoleTab: true, RoleTable: true,
+	RoleTablist: true, RoleTabPanel: true, RoleTerm: true, RoleTextbox: true, RoleTime: true, RoleTimer: true,
+	RoleToolbar: true, RoleTooltip: true, RoleTree: true, RoleTreeGrid: true, RoleTreeItem: true,
+}
+
+func (r Role) validate() error {
+	if r == "" {
+		return nil
+	}
+	fields := strings.Fields(string(r))
+	if len(fields) == 0 {
+		return fmt.Errorf("Role cannot be only whitespace")
+	}
+	for _, f := range fields {
+		if !roles[Role(f)] {
+			return fmt.Errorf("Role %q is not a valid ARIA role", f)
+		}
+	}
+	return nil
+}
+
+// AriaState is the value of an ARIA state such as aria-expanded. Unlike a bool, the zero value
+// outputs nothing, which is different from AriaFalse for most states.
+type AriaState string
+
+const (
+	AriaTrue  AriaState = "true"
+	AriaFalse AriaState = "false"
+	// AriaMixed can only be used for AriaChecked and AriaPressed, such as for a checkbox that
+	// controls other checkboxes where only some are checked.
+	AriaMixed AriaState = "mixed"
+)
+
+func (a AriaState) validate() error {
+	switch a {
+	case "", AriaTrue, AriaFalse, AriaMixed:
+		return nil
+	}
+	return fmt.Errorf("%q is not a valid AriaState", string(a))
+}
+
+// AriaLive indicates that an element will be updated and how assistive technologies should tell
+// the user about the updates.
+type AriaLive string
+
+const (
+	// AriaLiveOff does not tell the user about updates unless they are focused on the element.
+	AriaLiveOff AriaLive = "off"
+	// AriaLivePolite tells the user about updates when they are idle.
+	AriaLivePolite AriaLive = "polite"
+	// AriaLiveAssertive tells the user about updates immediately.
+	AriaLiveAssertive AriaLive = "assertive"
+)
+
+func (a AriaLive) validate() error {
+	switch a {
+	case "", AriaLiveOff, AriaLivePolite, AriaLiveAssertive:
+		return nil
+	}
+	return fmt.Errorf("%q is not a valid AriaLive", string(a))
+}
+
+// ariaFields are the keys of GlobalAttrs.Aria that have a field in GlobalAttrs, such as "label" for AriaLabel.
+var ariaFields = func() map[string]string {
+	m := map[string]string{}
+	t := reflect.TypeOf(GlobalAttrs{})
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if name := sf.Tag.Get("html"); strings.HasPrefix(name, "aria-") {
+			m[strings.TrimPrefix(name, "aria-")] = sf.Name
+		}
+	}
+	return m
+}()
+
+// validateAria validates the Role and ARIA fields of g.
+func (g GlobalAttrs) validateAria() error {
+	if err := g.Role.validate(); err != nil {
+		return fmt.Errorf("GlobalAttrs.Role: %w", err)
+	}
+	if err := g.AriaLive.validate(); err != nil {
+		return fmt.Errorf("GlobalAttrs.AriaLive: %w", err)
+	}
+	for _, s := range []struct {
+		name       string
+		state      AriaState
+		allowMixed bool
+	}{
+		{"AriaChecked", g.AriaChecked, true},
+		{"AriaDisabled", g.AriaDisabled, false},
+		{"AriaExpanded", g.AriaExpanded, false},
+		{"AriaHidden", g.AriaHidden, false},
+		{"AriaPressed", g.AriaPressed, true},
+		{"AriaSelected", g.AriaSelected, false},
+	} {
+		if err := s.state.validate(); err != nil {
+			return fmt.Errorf("GlobalAttrs.%s: %w", s.name, err)
+		}
+		if s.state == AriaMixed && !s.allowMixed {
+			return fmt.Errorf("GlobalAttrs.%s cannot be AriaMixed", s.name)
+		}
+	}
+	for k := range g.Aria {
+		if field, ok := ariaFields[k]; ok {
+			return fmt.Errorf("GlobalAttrs.Aria key %q must be set with GlobalAttrs.%s", k, field)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package html
+
+import (
+	"context"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestAriaAttr(t *testing.T) {
+	tests := []struct {
+		desc  string
+		attrs GlobalAttrs
+		want  template.HTMLAttr
+	}{
+		{
+			desc: "Role and states",
+			attrs: GlobalAttrs{
+				ID:             "menu-button",
+				Role:           RoleButton,
+				AriaLabel:      "Open menu",
+				AriaControls:   "menu",
+				AriaExpanded:   AriaFalse,
+				AriaPressed:    AriaMixed,
+				AriaLive:       AriaLivePolite,
+				AriaLabelledBy: "a b",
+				Aria:           map[string]string{"haspopup": "menu"},
+			},
+			want: `id="menu-button" role="button" aria-label="Open menu" aria-labelledby="a b" aria-controls="menu" ` +
+				`aria-live="polite" aria-expanded="false" aria-pressed="mixed" aria-haspopup="menu"`,
+		},
+		{
+			desc:  "Invalid values are output without panicking",
+			attrs: GlobalAttrs{Role: "bogus", AriaHidden: "maybe"},
+			want:  `role="bogus" aria-hidden="maybe"`,
+		},
+		{
+			desc:  "Free text is escaped",
+			attrs: GlobalAttrs{AriaLabel: `Say "hi" & bye`, AriaDescribedBy: `x" onclick="alert(1)`},
+			want:  `aria-label="Say &#34;hi&#34; &amp; bye" aria-describedby="x&#34; onclick=&#34;alert(1)"`,
+		},
+		{
+			desc:  "Fallback roles",
+			attrs: GlobalAttrs{Role: "switch checkbox", AriaChecked: AriaTrue},
+			want:  `role="switch checkbox" aria-checked="true"`,
+		},
+	}
+
+	for _, test := range tests {
+		got := test.attrs.Attr()
+		if test.want != got {
+			t.Errorf("TestAriaAttr(%s): \n\tgot  %q\n\twant %q", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestAriaValidate(t *testing.T) {
+	tests := []struct {
+		desc    string
+		attrs   GlobalAttrs
+		wantErr bool
+	}{
+		{
+			desc:  "Valid",
+			attrs: GlobalAttrs{Role: RoleTab, AriaSelected: AriaTrue, AriaChecked: AriaMixed, AriaLive: AriaLiveOff},
+		},
+		{
+			desc:    "Unknown role",
+			attrs:   GlobalAttrs{Role: "bogus"},
+			wantErr: true,
+		},
+		{
+			desc:    "Abstract role",
+			attrs:   GlobalAttrs{Role: "widget"},
+			wantErr: true,
+		},
+		{
+			desc:    "Unknown fallback role",
+			attrs:   GlobalAttrs{Role: "button bogus"},
+			wantErr: true,
+		},
+		{
+			desc:    "Whitespace role",
+			attrs:   GlobalAttrs{Role: " "},
+			wantErr: true,
+		},
+		{
+			desc:    "Invalid AriaState",
+			attrs:   GlobalAttrs{AriaHidden: "yes"},
+			wantErr: true,
+		},
+		{
+			desc:    "AriaMixed on a state that does not allow it",
+			attrs:   GlobalAttrs{AriaExpanded: AriaMixed},
+			wantErr: true,
+		},
+		{
+			desc:    "Invalid AriaLive",
+			attrs:   GlobalAttrs{AriaLive: "loud"},
+			wantErr: true,
+		},
+		{
+			desc:    "Aria key that has a field",
+			attrs:   GlobalAttrs{Aria: map[string]string{"label": "Close"}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := test.attrs.validate()
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestAriaValidate(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.wantErr:
+			t.Errorf("TestAriaValidate(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
+
+func TestAriaRenderErrors(t *testing.T) {
+	doc := &Doc{
+		Head: &Head{},
+		Body: &Body{
+			Elements: []Element{
+				Dynamic(func(pipe Pipeline) []Element {
+					return []Element{&Div{GlobalAttrs: GlobalAttrs{Role: "bogus"}}}
+				}),
+			},
+		},
+	}
+	if err := doc.Init(); err != nil {
+		t.Fatalf("TestAriaRenderErrors: Init() got err == %s, want err == nil", err)
+	}
+	if err := doc.Execute(context.Background(), &strings.Builder{}, nil); err == nil {
+		t.Errorf("TestAriaRenderErrors: got err == nil, want err != nil")
+	}
+
+	doc = &Doc{Head: &Head{}, Body: &Body{Elements: []Element{&Div{GlobalAttrs: GlobalAttrs{AriaExpanded: AriaMixed}}}}}
+	if err := doc.Init(); err == nil {
+		t.Errorf("TestAriaRenderErrors: Init() got err == nil, want err != nil")
+	}
+}
@@ -1,7 +1,9 @@
 package html
 
 import (
+	"context"
 	"fmt"
+	htmltmpl "html/template"
 	"strings"
 	"text/template"
 )
@@ -12,9 +14,51 @@ type Attribute interface {
 	IsAttr()
 }
 
+// Attr is a custom attribute with a Name and Value. If the Value is empty, only the Name is output,
+// which is how boolean attributes are set.
+type Attr struct {
+	// Name is the name of the attribute, such as "title".
+	Name string
+	// Value is the value of the attribute. This is HTML escaped on output.
+	Value string
+}
+
+// String outputs the attribute as name="value".
+func (a Attr) String() string {
+	if a.Value == "" {
+		return a.Name
+	}
+	return a.Name + `="` + htmltmpl.HTMLEscapeString(a.Value) + `"`
+}
+
+// IsAttr implements Attribute.
+func (a Attr) IsAttr() {}
+
+// AttributeValidator is implemented by a GearType that validates the Attributes passed to a Component that
+// uses it. This is only meant to be implemented by *component.Gear.
+type AttributeValidator interface {
+	// ValidateAttributes returns an error if the attributes are not valid for the Gear.
+	ValidateAttributes(attrs []Attribute) error
+}
+
+// ShadowGear is implemented by a GearType that can render its shadow DOM inside each Component that uses it,
+// known as a declarative shadow root. This is only meant to be implemented by *component.Gear.
+type ShadowGear interface {
+	// ExecuteShadow writes the Gear's shadow DOM to pipe.W. pipe.Self must be the *Component being rendered,
+	// as its Attributes set the Gear's props. The Gear then sets pipe.Self to itself and pipe.GearData to its
+	// data on its copy of the Pipeline, which does not change the caller's. Errors are passed to pipe.Error().
+	// If the Gear does not render declaratively, nothing is output.
+	ExecuteShadow(pipe Pipeline) string
+}
+
 var componenetTmpl = template.Must(template.New("component").Parse(strings.TrimSpace(`
-<{{.Self.Gear.TagType}} {{.Self.GlobalAttrs.Attr}} {{.Self.Events.Attr}}>
-	{{with .Self.TagValue}}{{.}}{{end}}
+<{{.Self.Gear.TagType}} {{.Self.GlobalAttrs.Attr}} {{with .Self.CustomAttrs}}{{.}} {{end}}{{.Self.Events.Attr}}>
+	{{- .Self.ShadowRoot .}}
+	{{- $data := .}}
+	{{with .Self.TagValue}}{{.Execute $data}}{{end}}
+	{{- range .Self.Elements}}
+	{{.Execute $data}}
+	{{- end}}
 </{{.Self.Gear.TagType}}>
 `)))
 
@@ -23,7 +67,8 @@ var componenetTmpl = template.Must(template.New("component").Parse(strings.TrimS
 type Component struct {
 	GlobalAttrs
 
-	// Attributes are custom attributes to apply to the component.
+	// Attributes are custom attributes to apply to the component. If the Gear implements AttributeValidator,
+	// these are validated when the Component is rendered.
 	Attributes []Attribute
 
 	// Gear is the *component.Gear that implements the componenent. The name of that Gear will be both the tag type and
@@ -34,6 +79,11 @@ type Component struct {
 	// it is not defined.
 	TagValue Element
 
+	// Elements are placed inside the component's tag (the light DOM) and are displayed in the Gear's Slot elements.
+	// An Element with GlobalAttrs.Slot set is displayed in the Slot with that Name, all others are displayed in
+	// the Slot without a Name.
+	Elements []Element
+
 	Events *Events
 }
 
@@ -47,9 +97,35 @@ func (c *Component) validate() error {
 
 func (c *Component) isElement() {}
 
+// CustomAttrs outputs the Attributes for use in the component's tag.
+func (c *Component) CustomAttrs() string {
+	out := make([]string, 0, len(c.Attributes))
+	for _, a := range c.Attributes {
+		out = append(out, a.String())
+	}
+	return strings.Join(out, " ")
+}
+
+// ShadowRoot renders the declarative shadow root of the Gear if it implements ShadowGear.
+func (c *Component) ShadowRoot(pipe Pipeline) string {
+	sg, ok := c.Gear.(ShadowGear)
+	if !ok {
+		return EmptyString
+	}
+	pipe.Self = c
+	return sg.ExecuteShadow(pipe)
+}
+
 func (c *Component) Execute(pipe Pipeline) string {
 	pipe.Self = c
 
+	if av, ok := c.Gear.(AttributeValidator); ok {
+		if err := av.ValidateAttributes(c.Attributes); err != nil {
+			pipe.Error(fmt.Errorf("Component(%s): %w", c.Gear.Name(), err))
+			return EmptyString
+		}
+	}
+
 	ga := c.GlobalAttrs
 	ga.ID = string(c.Gear.TagType())
 	c.GlobalAttrs = ga
@@ -60,3 +136,40 @@ func (c *Component) Execute(pipe Pipeline) string {
 
 	return EmptyString
 }
+
+// validateComponents checks that the Gear of every Component in the body is output by the page, either
+// directly or by a Gear that includes it. Otherwise the component's tag is never defined in the browser.
+// If the page contains a Dynamic, which can output Gears we cannot see, this check is skipped.
+func validateComponents(body *Body) error {
+	if body == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	gears := map[string]bool{}
+	var components []*Component
+	for walked := range Walker(ctx, body) {
+		switch e := walked.Element.(type) {
+		case *dynamic:
+			if len(walked.ShadowPath) == 0 {
+				return nil
+			}
+		case GearType:
+			gears[e.Name()] = true
+		case *Component:
+			components = append(components, e)
+		}
+	}
+
+	for _, c := range components {
+		if c.Gear == nil {
+			continue // Component.validate() reports this.
+		}
+		if !gears[c.Gear.Name()] {
+			return fmt.Errorf("Component(%s) uses a Gear that is not added to the page or to a Gear on the page", c.Gear.Name())
+		}
+	}
+	return nil
+}
@@ -13,6 +13,10 @@ type fakeGear struct {
 	name string
 }
 
+func (f fakeGear) Name() string {
+	return f.name
+}
+
 func (f fakeGear) TagType() template.HTMLAttr {
 	return template.HTMLAttr(f.name)
 }
@@ -41,6 +45,19 @@ func TestComponent(t *testing.T) {
 			want: strings.TrimSpace(`
 <myComponent accesskey="key" id="myComponent" onerror="handleError">
 	value
+</myComponent>
+`),
+		},
+		{
+			desc: "Attributes",
+			component: &Component{
+				Gear:       fakeGear{name: "myComponent"},
+				Attributes: []Attribute{Attr{Name: "title", Value: `"hello"`}, Attr{Name: "open"}},
+			},
+
+			want: strings.TrimSpace(`
+<myComponent id="myComponent" title="&#34;hello&#34;" open >
+	
 </myComponent>
 `),
 		},
@@ -55,3 +72,42 @@ func TestComponent(t *testing.T) {
 		}
 	}
 }
+
+func TestValidateComponents(t *testing.T) {
+	gear := fakeGear{name: "my-gear"}
+
+	tests := []struct {
+		desc string
+		body *Body
+		err  bool
+	}{
+		{
+			desc: "Gear is on the page",
+			body: &Body{Elements: []Element{gear, &Component{Gear: gear}}},
+		},
+		{
+			desc: "Gear is missing",
+			body: &Body{Elements: []Element{&Component{Gear: gear}}},
+			err:  true,
+		},
+		{
+			desc: "Page has a Dynamic",
+			body: &Body{
+				Elements: []Element{
+					Dynamic(func(pipe Pipeline) []Element { return nil }),
+					&Component{Gear: gear},
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		err := validateComponents(test.body)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestValidateComponents(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.err:
+			t.Errorf("TestValidateComponents(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"syscall/js"
 )
 
@@ -183,6 +184,44 @@ func elementByID(shadowPath []string, id string) (js.Value, error) {
 	return element, nil
 }
 
+// closedRoots holds the functions that the loaders of components with closed shadow roots have given
+// us to look up their roots, keyed by the loader's name.
+var closedRoots = struct {
+	sync.Mutex
+	lookups map[string]js.Value
+}{lookups: map[string]js.Value{}}
+
+// closedRoot returns the closed shadow root of the component element. The component's loader hands a
+// lookup for its roots to the first caller that passes it a function, which should be us. If another
+// script asked first, we return an error, as that script can reach the inside of the component.
+func closedRoot(element js.Value) (js.Value, error) {
+	// This must match component.Gear.LoaderName().
+	name := strings.ToLower(element.Get("tagName").String())
+	loaderName := strings.ReplaceAll(name, "-", "") + "Loader"
+
+	closedRoots.Lock()
+	defer closedRoots.Unlock()
+
+	lookup, ok := closedRoots.lookups[loaderName]
+	if !ok {
+		loader := js.Global().Get(loaderName)
+		if loader.Type() != js.TypeFunction {
+			return js.Undefined(), fmt.Errorf("component %s does not have a loader", name)
+		}
+		connect := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+			lookup = args[0]
+			return nil
+		})
+		defer connect.Release()
+
+		if !loader.Invoke(connect).Truthy() {
+			return js.Undefined(), fmt.Errorf("the closed shadow roots of component %s were given to another script", name)
+		}
+		closedRoots.lookups[loaderName] = lookup
+	}
+	return lookup.Invoke(element), nil
+}
+
 func rootByPath(shadowPath []string) (js.Value, error) {
 	root := js.Global().Get("document")
 	if !root.Truthy() {
@@ -190,7 +229,20 @@ func rootByPath(shadowPath []string) (js.Value, error) {
 	}
 	for _, component := range shadowPath {
 		log.Printf("running getElementById(%s): %v", component, root.Call("getElementById", component))
-		root = root.Call("getElementById", component).Get("shadowRoot")
+		element := root.Call("getElementById", component)
+		if !element.Truthy() {
+			fullPath := strings.Join(shadowPath, ".shadowRoot.")
+			return js.Undefined(), fmt.Errorf("rootByPath(%s): component was undefined", fullPath)
+		}
+		root = element.Get("shadowRoot")
+		if !root.Truthy() {
+			// A closed shadow root, which is only reachable through the component's loader.
+			var err error
+			root, err = closedRoot(element)
+			if err != nil {
+				return js.Undefined(), fmt.Errorf("rootByPath(%s): %w", strings.Join(shadowPath, ".shadowRoot."), err)
+			}
+		}
 		if !root.Truthy() {
 			fullPath := strings.Join(shadowPath, ".shadowRoot.")
 			return js.Undefined(), fmt.Errorf("rootByPath(%s): component was undefined", fullPath)
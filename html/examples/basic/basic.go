@@ -1,11 +1,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
-	"time"
 
 	"github.com/johnsiilver/webgear/handlers"
 
@@ -62,14 +61,8 @@ func main() {
 	// Our doc will now be served at the index page.
 	h.MustHandle("/", doc)
 
-	// Serve the content using the http.Server.
-	server := &http.Server{
-		Addr:           fmt.Sprintf(":%d", *port),
-		Handler:        h.ServerMux(),
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+	// Serve the content until we receive a signal to stop.
+	if err := handlers.Serve(context.Background(), h, handlers.Addr(fmt.Sprintf(":%d", *port))); err != nil {
+		log.Fatal(err)
 	}
-	log.Printf("http server serving on :%d", *port)
-	log.Fatal(server.ListenAndServe())
 }
@@ -1,11 +1,10 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
-	"time"
 
 	"github.com/johnsiilver/webgear/handlers"
 	"github.com/johnsiilver/webgear/html/builder"
@@ -83,14 +82,8 @@ func main() {
 	// Our doc will now be served at the index page.
 	h.MustHandle("/", build.Doc())
 
-	// Serve the content using the http.Server.
-	server := &http.Server{
-		Addr:           fmt.Sprintf(":%d", *port),
-		Handler:        h.ServerMux(),
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
+	// Serve the content until we receive a signal to stop.
+	if err := handlers.Serve(context.Background(), h, handlers.Addr(fmt.Sprintf(":%d", *port))); err != nil {
+		log.Fatal(err)
 	}
-	log.Printf("http server serving on :%d", *port)
-	log.Fatal(server.ListenAndServe())
 }
@@ -2,7 +2,6 @@ package html
 
 import (
 	"context"
-	"log"
 	"reflect"
 )
 
@@ -19,16 +18,19 @@ func ExtractEvents(element Element) *Events {
 		return nil
 	}
 
-	log.Println("ElementID: ", GetElementID(element))
-
 	ga := val.FieldByName("Events")
 	if !ga.IsValid() || ga.IsNil() {
 		return nil
 	}
-	log.Println("Events is: ", ga.Kind())
 	return ga.Interface().(*Events)
 }
 
+// IsDynamic returns true if the Element was created with Dynamic().
+func IsDynamic(e Element) bool {
+	_, ok := e.(*dynamic)
+	return ok
+}
+
 // GetElementID will return the Element's GlobalAttr.ID if it has one. Empty string if not.
 // If the Element is a *Gear, Gear.GearID().
 func GetElementID(e Element) string {
@@ -70,6 +72,8 @@ type Walked struct {
 	Parent Element
 	// ShadowPath is the path of component shadowRoots between the Walker "root" and this Element, not including it.
 	ShadowPath []string
+	// Depth is how many Elements are between the Walker "root" and this Element. The root has Depth 0.
+	Depth int
 }
 
 // Walker walks all elements from the root and returns them on the returned channel, including the root element.
@@ -78,22 +82,28 @@ func Walker(ctx context.Context, root Element) chan Walked {
 	ch := make(chan Walked, 1)
 	go func() {
 		defer close(ch)
-		walk(ctx, root, nil, nil, ch)
+		walk(ctx, root, nil, nil, 0, ch)
 	}()
 	return ch
 }
 
-func walk(ctx context.Context, element Element, parent Element, shadowPath []string, ch chan Walked) {
+func walk(ctx context.Context, element Element, parent Element, shadowPath []string, depth int, ch chan Walked) {
 	select {
 	case <-ctx.Done():
 		return
-	case ch <- Walked{Element: element, Parent: parent, ShadowPath: shadowPath}:
+	case ch <- Walked{Element: element, Parent: parent, ShadowPath: shadowPath, Depth: depth}:
 	}
 
 	// If the element we have is Gear, then it begins the start of a new shadowRoot.
 	// We require all Gear's to have names, so we can start tracking the shadowRoots
 	// to allow attached events in Wasm to work.
 	if v, ok := element.(GearType); ok {
+		// A Gear that contains itself is a cycle, which component.New() reports. Don't walk forever.
+		for _, name := range shadowPath {
+			if name == string(v.TagType()) {
+				return
+			}
+		}
 		n := make([]string, len(shadowPath)+1)
 		copy(n, shadowPath)
 		n[len(shadowPath)] = string(v.TagType())
@@ -101,7 +111,7 @@ func walk(ctx context.Context, element Element, parent Element, shadowPath []str
 	}
 
 	for _, child := range childElements(element, true) {
-		walk(ctx, child, element, shadowPath, ch)
+		walk(ctx, child, element, shadowPath, depth+1, ch)
 	}
 }
 
@@ -117,7 +127,10 @@ func childElements(element Element, gearDescend bool) []Element {
 	// Some types have a single child called "Element".
 	field := val.FieldByName(elementField)
 	if field.IsValid() {
-		return []Element{field.Interface().(Element)}
+		if e, ok := field.Interface().(Element); ok {
+			return []Element{e}
+		}
+		return nil
 	}
 
 	// Most types have children called "Elements".
@@ -37,13 +37,20 @@ func TestWalker(t *testing.T) {
 		"film2-option",
 	}
 
+	wantDepths := []int{0, 1, 1, 2, 3, 4, 4}
+
 	got := []string{}
+	gotDepths := []int{}
 	for walked := range Walker(context.Background(), doc.Body) {
 		got = append(got, GetElementID(walked.Element))
+		gotDepths = append(gotDepths, walked.Depth)
 	}
 
 	if diff := pretty.Compare(want, got); diff != "" {
 		t.Errorf("TestWalker: -want/+got:\n%s", diff)
 		t.Errorf(pretty.Sprint(got))
 	}
+	if diff := pretty.Compare(wantDepths, gotDepths); diff != "" {
+		t.Errorf("TestWalker(depths): -want/+got:\n%s", diff)
+	}
 }
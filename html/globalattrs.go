@@ -1,7 +1,10 @@
 package html
 
 import (
+	"fmt"
 	"html/template"
+	"sort"
+	"strings"
 )
 
 // Direction specifies the text direction for the content in an element.
@@ -49,6 +52,9 @@ type GlobalAttrs struct {
 	ID string
 	// Lang specifies the language of the element's content.
 	Lang string
+	// Slot specifies the name of the Slot in a component that the element is displayed in. This is only valid
+	// on elements that are direct children of a Component.
+	Slot string
 	// SpellCheck specifies whether the element is to have its spelling and grammar checked or not.
 	SpellCheck bool
 	// Style specifies an inline CSS style for an element.
@@ -62,12 +68,157 @@ type GlobalAttrs struct {
 	// Translate specifies extra information about an element.
 	Translate YesNo
 
+	// Role is the ARIA role of the element. See the Role type for details.
+	Role Role `escape:"html"`
+	// AriaLabel is a label for the element that is read by assistive technologies, for elements that
+	// do not have visible text such as an icon button.
+	AriaLabel string `html:"aria-label" escape:"html"`
+	// AriaLabelledBy is the IDs, separated by spaces, of the elements that label this element.
+	AriaLabelledBy string `html:"aria-labelledby" escape:"html"`
+	// AriaDescribedBy is the IDs, separated by spaces, of the elements that describe this element.
+	AriaDescribedBy string `html:"aria-describedby" escape:"html"`
+	// AriaControls is the IDs, separated by spaces, of the elements whose content or presence this element controls.
+	AriaControls string `html:"aria-controls" escape:"html"`
+	// AriaLive indicates the element will be updated, such as by a DocUpdater, and how to tell the user.
+	AriaLive AriaLive `html:"aria-live" escape:"html"`
+	// AriaChecked is the state of a checkbox, radio button or similar widget. It can be AriaMixed.
+	AriaChecked AriaState `html:"aria-checked" escape:"html"`
+	// AriaDisabled indicates the element is visible but cannot be changed or operated.
+	AriaDisabled AriaState `html:"aria-disabled" escape:"html"`
+	// AriaExpanded indicates if the element, or the element it controls, is expanded or collapsed.
+	AriaExpanded AriaState `html:"aria-expanded" escape:"html"`
+	// AriaHidden indicates the element is hidden from assistive technologies but not visually.
+	AriaHidden AriaState `html:"aria-hidden" escape:"html"`
+	// AriaPressed is the state of a toggle button. It can be AriaMixed.
+	AriaPressed AriaState `html:"aria-pressed" escape:"html"`
+	// AriaSelected indicates if the element, such as a tab, is selected.
+	AriaSelected AriaState `html:"aria-selected" escape:"html"`
+
+	// Data holds data-* attributes. Keys do not include the "data-" prefix, so {"user-id": "10"} is
+	// output as data-user-id="10". Keys cannot contain upper case letters.
+	Data map[string]string
+	// Aria holds aria-* attributes that do not have a field, such as aria-haspopup. Keys do not include the
+	// "aria-" prefix, so {"haspopup": "menu"} is output as aria-haspopup="menu". Keys must be lower case
+	// letters and cannot be for attributes that have a field, such as "label".
+	Aria map[string]string
+	// Custom holds any other attribute that does not have a field, keyed by the attribute name.
+	// This is for attributes this package does not model yet and those used by other libraries.
+	Custom map[string]string
+
 	// XXXWasmUpdated indicates that a DocUpdater has updated the Element this is attached to, but
 	// we have not flushed the changes to the DOM. This field is not a GlobalAttrs for HTML and is only public
 	// so that is may be manipulated via reflection.
 	XXXWasmUpdated bool
 }
 
+// Attr outputs the attributes. Keys in Data, Aria or Custom that are not valid are left out, as they
+// cannot be output safely. Other values, such as a Role that is not valid, are output as is. These
+// errors are reported by Doc.Init() or, for Elements returned by a DynamicFunc, by Doc.Execute().
 func (g GlobalAttrs) Attr() template.HTMLAttr {
-	return template.HTMLAttr(structToString(g))
+	out := structToString(g)
+	extra := []string{}
+	for _, m := range []struct {
+		prefix string
+		attrs  map[string]string
+		valid  func(string) error
+	}{{"data-", g.Data, validDataKey}, {"aria-", g.Aria, validAriaKey}, {"", g.Custom, validAttrName}} {
+		keys := make([]string, 0, len(m.attrs))
+		for k := range m.attrs {
+			if m.valid(k) != nil {
+				continue
+			}
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			extra = append(extra, Attr{Name: m.prefix + k, Value: m.attrs[k]}.String())
+		}
+	}
+	if len(extra) == 0 {
+		return template.HTMLAttr(out)
+	}
+	return template.HTMLAttr(strings.TrimSpace(out + " " + strings.Join(extra, " ")))
+}
+
+func (g GlobalAttrs) validate() error {
+	if err := g.validateAria(); err != nil {
+		return err
+	}
+	for k := range g.Data {
+		if err := validDataKey(k); err != nil {
+			return fmt.Errorf("GlobalAttrs.Data key %q: %w", k, err)
+		}
+	}
+	for k := range g.Aria {
+		if err := validAriaKey(k); err != nil {
+			return fmt.Errorf("GlobalAttrs.Aria key %q: %w", k, err)
+		}
+	}
+	for k := range g.Custom {
+		if err := validAttrName(k); err != nil {
+			return fmt.Errorf("GlobalAttrs.Custom key %q: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// validDataKey validates a key in GlobalAttrs.Data.
+func validDataKey(k string) error {
+	if err := validAttrName(k); err != nil {
+		return err
+	}
+	if strings.ToLower(k) != k {
+		return fmt.Errorf("key cannot contain upper case letters")
+	}
+	return nil
+}
+
+// validAriaKey validates a key in GlobalAttrs.Aria.
+func validAriaKey(k string) error {
+	if k == "" {
+		return fmt.Errorf("key cannot be empty")
+	}
+	for _, r := range k {
+		if r < 'a' || r > 'z' {
+			return fmt.Errorf("key must be lower case letters")
+		}
+	}
+	return nil
+}
+
+// addAttr stores an attribute that does not have a field in Data, Aria or Custom. Attributes with
+// a prefix but a key that Data or Aria does not allow are stored in Custom.
+func (g *GlobalAttrs) addAttr(name, value string) {
+	var (
+		m   = &g.Custom
+		key = name
+	)
+	switch {
+	case strings.HasPrefix(name, "data-"):
+		if k := strings.TrimPrefix(name, "data-"); validDataKey(k) == nil {
+			m, key = &g.Data, k
+		}
+	case strings.HasPrefix(name, "aria-"):
+		if k := strings.TrimPrefix(name, "aria-"); validAriaKey(k) == nil && ariaFields[k] == "" {
+			m, key = &g.Aria, k
+		}
+	}
+	if *m == nil {
+		*m = map[string]string{}
+	}
+	(*m)[key] = value
+}
+
+// validAttrName validates that name is a valid attribute name in HTML syntax.
+func validAttrName(name string) error {
+	if name == "" {
+		return fmt.Errorf("attribute name cannot be empty")
+	}
+	for _, r := range name {
+		switch {
+		case r <= 0x20, r == 0x7f, r == '"', r == '\'', r == '>', r == '/', r == '=':
+			return fmt.Errorf("attribute name cannot contain %q", r)
+		}
+	}
+	return nil
 }
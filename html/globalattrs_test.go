@@ -25,6 +25,7 @@ func TestGlobalAttrs(t *testing.T) {
 				Hidden:          true,
 				ID:              "id",
 				Lang:            "english",
+				Slot:            "slot",
 				SpellCheck:      true,
 				Style:           "style",
 				TabIndex:        1,
@@ -32,7 +33,25 @@ func TestGlobalAttrs(t *testing.T) {
 				Translate:       Yes,
 			},
 			want: `accesskey="key" class="class" contenteditable="true" dir="rtl" draggable="true" hidden id="id" ` +
-				`lang="english" spellcheck="true" style="style" tabindex="1" title="title" translate="yes"`,
+				`lang="english" slot="slot" spellcheck="true" style="style" tabindex="1" title="title" translate="yes"`,
+		},
+		{
+			desc: "Data, Aria and Custom attributes",
+			attrs: GlobalAttrs{
+				ID:     "id",
+				Data:   map[string]string{"user-id": "10", "a": `"quoted"`},
+				Aria:   map[string]string{"haspopup": "menu"},
+				Custom: map[string]string{"hx-get": "/items", "inert": ""},
+			},
+			want: `id="id" data-a="&#34;quoted&#34;" data-user-id="10" aria-haspopup="menu" hx-get="/items" inert`,
+		},
+		{
+			desc: "Invalid keys are left out",
+			attrs: GlobalAttrs{
+				Data:   map[string]string{"Upper": "1"},
+				Custom: map[string]string{"a onclick": "x", "ok": "1"},
+			},
+			want: `ok="1"`,
 		},
 	}
 
@@ -43,3 +62,51 @@ func TestGlobalAttrs(t *testing.T) {
 		}
 	}
 }
+
+func TestGlobalAttrsValidate(t *testing.T) {
+	tests := []struct {
+		desc    string
+		attrs   GlobalAttrs
+		wantErr bool
+	}{
+		{
+			desc:  "Valid",
+			attrs: GlobalAttrs{Data: map[string]string{"user-id": "1"}, Aria: map[string]string{"haspopup": "a"}, Custom: map[string]string{"@click": "a"}},
+		},
+		{
+			desc:    "Data key with upper case",
+			attrs:   GlobalAttrs{Data: map[string]string{"userId": "1"}},
+			wantErr: true,
+		},
+		{
+			desc:    "Data key with a space",
+			attrs:   GlobalAttrs{Data: map[string]string{"user id": "1"}},
+			wantErr: true,
+		},
+		{
+			desc:    "Aria key with a hyphen",
+			attrs:   GlobalAttrs{Aria: map[string]string{"aria-label": "a"}},
+			wantErr: true,
+		},
+		{
+			desc:    "Custom key with an equals sign",
+			attrs:   GlobalAttrs{Custom: map[string]string{"a=b": "a"}},
+			wantErr: true,
+		},
+		{
+			desc:    "Empty Custom key",
+			attrs:   GlobalAttrs{Custom: map[string]string{"": "a"}},
+			wantErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		err := test.attrs.validate()
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestGlobalAttrsValidate(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.wantErr:
+			t.Errorf("TestGlobalAttrsValidate(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
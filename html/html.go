@@ -222,6 +222,15 @@ type Pipeline struct {
 	// pipeline is embedded in. Then GearData would only belong to that pipelin.  GearData has no
 	// affect on anything in this package.
 	GearData interface{}
+
+	// emitted tracks the Gears and other shared content that have been output in this call chain.
+	emitted *emitted
+}
+
+// emitted is a registry of the Gears and other shared content that have been output, keyed by name.
+type emitted struct {
+	mu     sync.Mutex
+	values map[string]interface{}
 }
 
 // NewPipeline creates a new Pipeline object.
@@ -229,14 +238,67 @@ func NewPipeline(ctx context.Context, req *http.Request, w io.Writer) Pipeline {
 	ctx, cancel := context.WithCancel(ctx)
 
 	return Pipeline{
-		Ctx:    ctx,
-		cancel: cancel,
-		errCh:  make(chan error, 1),
-		Req:    req,
-		W:      w,
+		Ctx:     ctx,
+		cancel:  cancel,
+		errCh:   make(chan error, 1),
+		Req:     req,
+		W:       w,
+		emitted: &emitted{values: map[string]interface{}{}},
 	}
 }
 
+// EmitOnce records that the Gear is being output in this call chain. It returns true the first time it is
+// called for a Gear and false afterwards, which allows a Gear's template and loader to be output only once
+// per page. An error is returned if a different Gear with the same Name() was already output. This is for
+// internal use by the component package. A Pipeline not created with NewPipeline() always returns true.
+func (p Pipeline) EmitOnce(g GearType) (bool, error) {
+	first, err := p.EmitKeyOnce("gear:"+g.Name(), g)
+	if err != nil {
+		return false, fmt.Errorf("two different Gears have the same name %q", g.Name())
+	}
+	return first, nil
+}
+
+// LoadOrStore returns the value stored in this call chain for key, if present. Otherwise it stores and returns
+// v. loaded is true if the value was already stored. This allows Elements to share state, such as data being
+// loaded in the background, for the length of a single Execute(). This is for internal use by the component
+// package. A Pipeline not created with NewPipeline() never stores v.
+func (p Pipeline) LoadOrStore(key string, v interface{}) (actual interface{}, loaded bool) {
+	if p.emitted == nil {
+		return v, false
+	}
+
+	p.emitted.mu.Lock()
+	defer p.emitted.mu.Unlock()
+
+	if prev, ok := p.emitted.values[key]; ok {
+		return prev, true
+	}
+	p.emitted.values[key] = v
+	return v, false
+}
+
+// EmitKeyOnce is like EmitOnce() except it records any shared content v, such as a stylesheet, by key.
+// v must be comparable. An error is returned if a different v was already output with key.
+func (p Pipeline) EmitKeyOnce(key string, v interface{}) (bool, error) {
+	if p.emitted == nil {
+		return true, nil
+	}
+
+	p.emitted.mu.Lock()
+	defer p.emitted.mu.Unlock()
+
+	prev, ok := p.emitted.values[key]
+	if !ok {
+		p.emitted.values[key] = v
+		return true, nil
+	}
+	if prev != v {
+		return false, fmt.Errorf("two different values were output with key %q", key)
+	}
+	return false, nil
+}
+
 // Error adds an error to the Pipeline. If there is already an error recorded, the error will be dropped.
 func (p Pipeline) Error(err error) {
 	p.cancel()
@@ -273,9 +335,19 @@ type Doc struct {
 
 	pool sync.Pool
 
+	// prefetchers are the Prefetchers in the Body that are not inside another Prefetcher.
+	prefetchers []Prefetcher
+
 	initDone bool
 }
 
+// Prefetcher is implemented by an Element that can start loading its data before the Doc is rendered.
+// This is only meant to be implemented by *component.Gear.
+type Prefetcher interface {
+	// Prefetch starts loading data in the background. It must not block.
+	Prefetch(pipe Pipeline)
+}
+
 // Init sets up all the internals for execution. Must be called before Execute() and should only be called once.
 func (d *Doc) Init() error {
 	if d.initDone {
@@ -300,6 +372,17 @@ func (d *Doc) Init() error {
 	if err := d.Body.Init(); err != nil {
 		return err
 	}
+	d.prefetchers = findPrefetchers(d.Body)
+
+	if err := validateTree(d.Head, d.Body); err != nil {
+		return err
+	}
+
+	if !d.Component {
+		if err := validateComponents(d.Body); err != nil {
+			return err
+		}
+	}
 
 	d.pool = sync.Pool{
 		New: func() interface{} {
@@ -311,6 +394,97 @@ func (d *Doc) Init() error {
 	return nil
 }
 
+// findPrefetchers returns all Prefetchers in the Body that are not inside another Prefetcher, as each
+// Prefetcher is responsible for the Prefetchers it contains.
+func findPrefetchers(body *Body) []Prefetcher {
+	if body == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var prefetchers []Prefetcher
+	for walked := range Walker(ctx, body) {
+		if p, ok := walked.Element.(Prefetcher); ok && len(walked.ShadowPath) == 0 {
+			prefetchers = append(prefetchers, p)
+		}
+	}
+	return prefetchers
+}
+
+// prefetch starts loading data for all of the Doc's Prefetchers.
+func (d *Doc) prefetch(pipe Pipeline) {
+	for _, p := range d.prefetchers {
+		p.Prefetch(pipe)
+	}
+}
+
+// validateTree validates every Tag and the GlobalAttrs of every Element below the roots, including the
+// Elements of Gears.
+func validateTree(head *Head, body *Body) error {
+	roots := []Element{body}
+	if head != nil {
+		roots = append(roots, head)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	for _, root := range roots {
+		for walked := range Walker(ctx, root) {
+			if err := validateOne(walked.Element); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateElements validates the elements and their children in the same way as validateTree(), but
+// does not descend into Gears, which are validated by their own Init(). This is used on every render
+// of a DynamicFunc, so it walks the tree directly instead of using Walker().
+func validateElements(elements []Element) error {
+	for _, e := range elements {
+		if err := validateOne(e); err != nil {
+			return err
+		}
+		if err := validateElements(childElements(e, false)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOne validates element if it is a Tag and its GlobalAttrs if it has them.
+func validateOne(element Element) error {
+	if t, ok := element.(*Tag); ok {
+		if err := t.validate(); err != nil {
+			return err
+		}
+	}
+
+	val := reflect.ValueOf(element)
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	field := val.FieldByName(globalAttrsField)
+	if !field.IsValid() {
+		return nil
+	}
+	if ga, ok := field.Interface().(GlobalAttrs); ok {
+		if err := ga.validate(); err != nil {
+			return fmt.Errorf("%T: %w", element, err)
+		}
+	}
+	return nil
+}
+
 // validate attempts to do basic validation of the Doc contents as best it can.
 func (d *Doc) validate() error {
 	if err := d.Body.validate(); err != nil {
@@ -374,6 +548,11 @@ func (d *dynamic) Execute(pipe Pipeline) string {
 
 	elements := d.f(pipe)
 	compileElements(elements)
+	// These Elements did not exist when Doc.Init() validated the page.
+	if err := validateElements(elements); err != nil {
+		pipe.Error(fmt.Errorf("DynamicFunc with type %T returned an invalid Element: %w", d.f, err))
+		return EmptyString
+	}
 	for _, e := range elements {
 		if pipe.Ctx.Err() != nil {
 			return EmptyString
@@ -405,6 +584,13 @@ func (t TextElement) isZero() bool {
 	return false
 }
 
+// EscapedText returns a TextElement containing s with HTML special characters escaped. TextElement
+// writes its content as is, so this should be used for text that comes from users or other sources
+// that may contain characters such as "<".
+func EscapedText(s string) TextElement {
+	return TextElement(template.HTMLEscapeString(s))
+}
+
 func structToString(i interface{}) string {
 	val := reflect.ValueOf(i)
 
@@ -530,7 +716,7 @@ func structToString(i interface{}) string {
 			} else {
 				continue
 			}
-		case reflect.Interface:
+		case reflect.Interface, reflect.Map:
 			continue
 		default:
 			if meth := field.MethodByName("Get" + t.Name()); meth.IsValid() {
@@ -548,9 +734,13 @@ func structToString(i interface{}) string {
 		}
 
 		// Naked is about if the attribute should be just a tag with no "=", like "sandbox" instead of "sandbox=".
-		if isNaked {
+		// Fields with escape:"html" hold free text, such as AriaLabel, so are escaped like an Attr.
+		switch {
+		case isNaked:
 			out = append(out, strings.ToLower(name))
-		} else {
+		case sf.Tag.Get("escape") == "html":
+			out = append(out, Attr{Name: strings.ToLower(name), Value: str}.String())
+		default:
 			out = append(out, fmt.Sprintf("%s=%q", strings.ToLower(name), str))
 		}
 	}
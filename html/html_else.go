@@ -28,6 +28,9 @@ func (d *Doc) Execute(ctx context.Context, w io.Writer, r *http.Request) (err er
 	}
 
 	pipe := NewPipeline(ctx, r, w)
+	// Stops work started for the page, such as prefetched DataFuncs for Gears that were never reached.
+	defer pipe.cancel()
+	d.prefetch(pipe)
 	pipe.Self = d
 
 	if err := docTmpl.Execute(w, pipe); err != nil {
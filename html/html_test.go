@@ -1,8 +1,10 @@
 package html
 
 import (
+	"context"
 	"regexp"
 	"strings"
+	"testing"
 	"unicode"
 )
 
@@ -48,3 +50,37 @@ func reduceSpace(s string) string {
 	}
 	return string(rs)
 }
+
+func TestEmitOnce(t *testing.T) {
+	a := &fakeGear{name: "my-gear"}
+	b := &fakeGear{name: "my-gear"}
+
+	tests := []struct {
+		desc string
+		gear GearType
+		want bool
+		err  bool
+	}{
+		{desc: "First", gear: a, want: true},
+		{desc: "Same Gear", gear: a, want: false},
+		{desc: "Different Gear same name", gear: b, err: true},
+	}
+
+	pipe := NewPipeline(context.Background(), nil, &strings.Builder{})
+	for _, test := range tests {
+		got, err := pipe.EmitOnce(test.gear)
+		switch {
+		case err == nil && test.err:
+			t.Errorf("TestEmitOnce(%s): got err == nil, want err != nil", test.desc)
+			continue
+		case err != nil && !test.err:
+			t.Errorf("TestEmitOnce(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		case err != nil:
+			continue
+		}
+		if got != test.want {
+			t.Errorf("TestEmitOnce(%s): got %v, want %v", test.desc, got, test.want)
+		}
+	}
+}
@@ -24,6 +24,9 @@ func (d *Doc) Execute(ctx context.Context, w io.Writer, r *http.Request) (err er
 	}
 
 	pipe := NewPipeline(ctx, r, w)
+	// Stops work started for the page, such as prefetched DataFuncs for Gears that were never reached.
+	defer pipe.cancel()
+	d.prefetch(pipe)
 	pipe.Self = d
 
 	if err := docTmpl.Execute(w, pipe); err != nil {
@@ -194,6 +197,7 @@ func (d *DocUpdater) UpdateDOM() error {
 		buff.Reset()
 		pipe := NewPipeline(context.TODO(), &http.Request{}, buff)
 		updateElement.Element.Execute(pipe)
+		pipe.cancel()
 
 		log.Println("element name to update: ", GetElementID(updateElement.Element))
 		js.Global().Get("document").Call("getElementById", GetElementID(updateElement.Element)).Set("outerHTML", buff.String())
@@ -0,0 +1,464 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// tagTypes returns a new Element for tags that have their own type in this package.
+var tagTypes = map[string]func() Element{
+	"a":        func() Element { return &A{} },
+	"base":     func() Element { return &Base{} },
+	"br":       func() Element { return &BR{} },
+	"button":   func() Element { return &Button{} },
+	"caption":  func() Element { return &Caption{} },
+	"col":      func() Element { return &Col{} },
+	"colgroup": func() Element { return &ColGroup{} },
+	"div":      func() Element { return &Div{} },
+	"fieldset": func() Element { return &FieldSet{} },
+	"form":     func() Element { return &Form{} },
+	"h1":       func() Element { return &H{Level: 1} },
+	"h2":       func() Element { return &H{Level: 2} },
+	"h3":       func() Element { return &H{Level: 3} },
+	"h4":       func() Element { return &H{Level: 4} },
+	"h5":       func() Element { return &H{Level: 5} },
+	"h6":       func() Element { return &H{Level: 6} },
+	"hr":       func() Element { return &HR{} },
+	"i":        func() Element { return &I{} },
+	"iframe":   func() Element { return &IFrame{} },
+	"img":      func() Element { return &Img{} },
+	"input":    func() Element { return &Input{} },
+	"label":    func() Element { return &Label{} },
+	"legend":   func() Element { return &Legend{} },
+	"li":       func() Element { return &Li{} },
+	"link":     func() Element { return &Link{} },
+	"meta":     func() Element { return &Meta{} },
+	"nav":      func() Element { return &Nav{} },
+	"optgroup": func() Element { return &OptGroup{} },
+	"option":   func() Element { return &Option{} },
+	"output":   func() Element { return &Output{} },
+	"p":        func() Element { return &P{} },
+	"script":   func() Element { return &Script{} },
+	"select":   func() Element { return &Select{} },
+	"slot":     func() Element { return &Slot{} },
+	"span":     func() Element { return &Span{} },
+	"style":    func() Element { return &Style{} },
+	"table":    func() Element { return &Table{} },
+	"tbody":    func() Element { return &TBody{} },
+	"td":       func() Element { return &TD{} },
+	"textarea": func() Element { return &TextArea{} },
+	"tfoot":    func() Element { return &TFoot{} },
+	"th":       func() Element { return &TH{} },
+	"thead":    func() Element { return &THead{} },
+	"title":    func() Element { return &Title{} },
+	"tr":       func() Element { return &TR{} },
+	"ul":       func() Element { return &Ul{} },
+}
+
+// Parse parses an HTML document into a *Doc. Tags are converted to the type in this package that represents
+// them, such as <div> to a *Div, with the attributes set in the type's fields and its GlobalAttrs. If a tag has no
+// type or has content that its type cannot represent, it is converted to a *Tag. Attributes that do not have a
+// field are stored in GlobalAttrs.Data, Aria or Custom. Comments are removed, as is text that is only
+// whitespace, unless it is shown by the browser, such as between two inline elements, where it becomes a
+// single space. This allows existing pages to be moved to this package a piece at a time.
+func Parse(r io.Reader) (*Doc, error) {
+	root, err := xhtml.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &Doc{Head: &Head{}, Body: &Body{}}
+	for n := root.FirstChild; n != nil; n = n.NextSibling {
+		if n.Type != xhtml.ElementNode || n.DataAtom != atom.Html {
+			continue
+		}
+		if err := setAttrs(reflect.ValueOf(&doc.GlobalAttrs).Elem(), n.Attr); err != nil {
+			return nil, fmt.Errorf("<html>: %w", err)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != xhtml.ElementNode {
+				continue
+			}
+			switch c.DataAtom {
+			case atom.Head:
+				if err := setAttrs(reflect.ValueOf(&doc.Head.GlobalAttrs).Elem(), c.Attr); err != nil {
+					return nil, fmt.Errorf("<head>: %w", err)
+				}
+				doc.Head.Elements = parseChildren(c)
+			case atom.Body:
+				if err := setAttrs(reflect.ValueOf(&doc.Body.GlobalAttrs).Elem(), c.Attr); err != nil {
+					return nil, fmt.Errorf("<body>: %w", err)
+				}
+				doc.Body.Elements = parseChildren(c)
+			}
+		}
+	}
+	return doc, nil
+}
+
+// ParseFragment parses HTML that is part of a <body>, such as the content of a template, into Elements.
+// The conversion is the same as Parse().
+func ParseFragment(r io.Reader) ([]Element, error) {
+	nodes, err := xhtml.ParseFragment(r, &xhtml.Node{Type: xhtml.ElementNode, Data: "body", DataAtom: atom.Body})
+	if err != nil {
+		return nil, err
+	}
+
+	parent := &xhtml.Node{Type: xhtml.ElementNode, Data: "body", DataAtom: atom.Body}
+	for _, n := range nodes {
+		parent.AppendChild(n)
+	}
+	return parseChildren(parent), nil
+}
+
+// parseChildren converts the children of n to Elements.
+func parseChildren(n *xhtml.Node) []Element {
+	elements := []Element{}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if e := parseNode(c, n); e != nil {
+			elements = append(elements, e)
+		}
+	}
+	return elements
+}
+
+// parseNode converts n to an Element. It returns nil for nodes that are not output, such as comments.
+func parseNode(n *xhtml.Node, parent *xhtml.Node) Element {
+	switch n.Type {
+	case xhtml.TextNode:
+		if strings.TrimSpace(n.Data) == "" {
+			if !keepSpace(n) {
+				return nil
+			}
+			if parent != nil && parent.DataAtom == atom.Pre {
+				return TextElement(n.Data)
+			}
+			return TextElement(" ")
+		}
+		if parent != nil && (parent.DataAtom == atom.Script || parent.DataAtom == atom.Style) {
+			return TextElement(n.Data)
+		}
+		return EscapedText(n.Data)
+	case xhtml.ElementNode:
+		if e, err := typedElement(n); err == nil {
+			return e
+		}
+		return genericTag(n)
+	}
+	return nil
+}
+
+// keepSpace reports if n, a text node that is only whitespace, is shown by browsers. This is true inside
+// a <pre> and between inline content, such as the space in "<b>a</b> <i>b</i>". Whitespace next to block
+// elements or at the start or end of an element is not shown, so it is dropped.
+func keepSpace(n *xhtml.Node) bool {
+	if n.Parent != nil && n.Parent.DataAtom == atom.Pre {
+		return true
+	}
+	prev, next := n.PrevSibling, n.NextSibling
+	for prev != nil && prev.Type == xhtml.CommentNode {
+		prev = prev.PrevSibling
+	}
+	for next != nil && next.Type == xhtml.CommentNode {
+		next = next.NextSibling
+	}
+	return inlineNode(prev) && inlineNode(next)
+}
+
+// inlineNode reports if n is text or an element that is displayed inline by default.
+func inlineNode(n *xhtml.Node) bool {
+	switch {
+	case n == nil:
+		return false
+	case n.Type == xhtml.TextNode:
+		return strings.TrimSpace(n.Data) != ""
+	case n.Type != xhtml.ElementNode:
+		return false
+	case strings.Contains(n.Data, "-"):
+		// Custom elements, such as a Component, are inline unless their CSS says otherwise.
+		return true
+	}
+	return inlineTags[n.DataAtom]
+}
+
+// inlineTags are the tags that browsers display inline by default.
+var inlineTags = map[atom.Atom]bool{
+	atom.A: true, atom.Abbr: true, atom.B: true, atom.Bdi: true, atom.Bdo: true, atom.Br: true, atom.Button: true,
+	atom.Cite: true, atom.Code: true, atom.Data: true, atom.Dfn: true, atom.Em: true, atom.I: true, atom.Img: true,
+	atom.Input: true, atom.Kbd: true, atom.Label: true, atom.Mark: true, atom.Output: true, atom.Q: true,
+	atom.S: true, atom.Samp: true, atom.Select: true, atom.Slot: true, atom.Small: true, atom.Span: true,
+	atom.Strong: true, atom.Sub: true, atom.Sup: true, atom.Textarea: true, atom.Time: true, atom.U: true,
+	atom.Var: true, atom.Wbr: true,
+}
+
+// genericTag converts n to a *Tag. Attributes that are fields of GlobalAttrs, such as "id", are set there so
+// that the Tag works with GetElementID(). Others are added to Attrs in order.
+func genericTag(n *xhtml.Node) *Tag {
+	t := &Tag{Name: n.Data, Void: voidTags[n.Data]}
+	ga := reflect.ValueOf(&t.GlobalAttrs).Elem()
+	for _, a := range n.Attr {
+		if a.Namespace == "" && setAttr(ga, a.Key, a.Val) {
+			continue
+		}
+		name := a.Key
+		if a.Namespace != "" {
+			name = a.Namespace + ":" + a.Key
+		}
+		t.Attrs = append(t.Attrs, Attr{Name: name, Value: a.Val})
+	}
+	if !t.Void {
+		t.Elements = parseChildren(n)
+	}
+	return t
+}
+
+// voidTags are tags that never have an end tag.
+var voidTags = map[string]bool{
+	"area": true, "base": true, "br": true, "col": true, "embed": true, "hr": true, "img": true,
+	"input": true, "link": true, "meta": true, "param": true, "source": true, "track": true, "wbr": true,
+}
+
+// typedElement converts n to the type registered for its tag in tagTypes. An error is returned if there is no type
+// or the type cannot represent all of n's attributes and content.
+func typedElement(n *xhtml.Node) (Element, error) {
+	newElement, ok := tagTypes[n.Data]
+	if !ok {
+		return nil, fmt.Errorf("no type for <%s>", n.Data)
+	}
+	e := newElement()
+	val := reflect.ValueOf(e).Elem()
+
+	if err := setAttrs(val, n.Attr); err != nil {
+		return nil, err
+	}
+	if err := setContent(val, n); err != nil {
+		return nil, err
+	}
+	if v, ok := e.(validator); ok {
+		if err := v.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// setAttrs sets the fields of the struct val from the attributes. Fields are matched to attributes in
+// the same way that structToString() outputs them. Attributes without a field are added to the
+// Data, Aria or Custom fields of GlobalAttrs.
+func setAttrs(val reflect.Value, attrs []xhtml.Attribute) error {
+	var ga *GlobalAttrs
+	switch v := val.Addr().Interface().(type) {
+	case *GlobalAttrs:
+		ga = v
+	default:
+		if field := val.FieldByName(globalAttrsField); field.IsValid() {
+			ga, _ = field.Addr().Interface().(*GlobalAttrs)
+		}
+	}
+
+	for _, a := range attrs {
+		if a.Namespace != "" {
+			return fmt.Errorf("attribute %s:%s is not supported", a.Namespace, a.Key)
+		}
+		if setAttr(val, a.Key, a.Val) {
+			continue
+		}
+		if ga == nil {
+			return fmt.Errorf("attribute %s=%q is not supported", a.Key, a.Val)
+		}
+		ga.addAttr(a.Key, a.Val)
+	}
+	return nil
+}
+
+// setAttr sets the field in the struct val for the attribute. It returns false if no field can hold the value.
+func setAttr(val reflect.Value, name, value string) bool {
+	t := val.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.Anonymous {
+			if sf.Type == reflect.TypeOf(GlobalAttrs{}) && setAttr(val.Field(i), name, value) {
+				return true
+			}
+			continue
+		}
+		if attrName(sf) != name {
+			continue
+		}
+
+		v := value
+		if suffix := sf.Tag.Get("suffix"); suffix != "" {
+			switch {
+			case strings.HasSuffix(v, suffix):
+				v = strings.TrimSuffix(v, suffix)
+			case suffix == "px" && isDigits(v):
+				// A number without units is in pixels.
+			default:
+				continue
+			}
+		}
+		if setField(val.Field(i), sf, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// attrName returns the name of the attribute that structToString() outputs for the field. If the field is
+// not output as an attribute, this returns the empty string.
+func attrName(sf reflect.StructField) string {
+	switch {
+	case sf.PkgPath != "", sf.Name == "Element", sf.Name == "Elements", sf.Name == "TagValue":
+		return ""
+	case strings.HasPrefix(sf.Name, "XXX"):
+		return ""
+	}
+	if tagName := sf.Tag.Get("html"); tagName != "" && tagName != "attr" {
+		return strings.ToLower(tagName)
+	}
+	return strings.ToLower(sf.Name)
+}
+
+// setField sets field to value. It returns false if the field's type cannot hold the value.
+func setField(field reflect.Value, sf reflect.StructField, value string) bool {
+	switch field.Kind() {
+	case reflect.String:
+		old := field.String()
+		field.SetString(value)
+		// Types such as Role only allow some values. Others are left for the caller to handle.
+		if v, ok := field.Interface().(validator); ok && v.validate() != nil {
+			field.SetString(old)
+			return false
+		}
+		return true
+	case reflect.Bool:
+		switch strings.ToLower(value) {
+		case "", "true", strings.ToLower(sf.Name):
+			field.SetBool(true)
+			return true
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(value, 10, field.Type().Bits())
+		if err != nil {
+			return false
+		}
+		if i == 0 {
+			i = Zero
+			if field.OverflowInt(i) {
+				return false
+			}
+		}
+		field.SetInt(i)
+		return true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		u, err := strconv.ParseUint(value, 10, field.Type().Bits())
+		if err != nil || u == 0 {
+			// A zero value is not output, so 0 cannot be represented.
+			return false
+		}
+		field.SetUint(u)
+		return true
+	case reflect.Ptr:
+		if field.Type() != reflect.TypeOf(&url.URL{}) {
+			return false
+		}
+		u, err := url.Parse(value)
+		if err != nil {
+			return false
+		}
+		field.Set(reflect.ValueOf(u))
+		return true
+	}
+	return false
+}
+
+// setContent sets the Elements, Element or TagValue field of the struct val to the content of n.
+func setContent(val reflect.Value, n *xhtml.Node) error {
+	children := []*xhtml.Node{}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch c.Type {
+		case xhtml.ElementNode:
+			children = append(children, c)
+		case xhtml.TextNode:
+			if strings.TrimSpace(c.Data) != "" || keepSpace(c) {
+				children = append(children, c)
+			}
+		}
+	}
+
+	if field := val.FieldByName(elementsField); field.IsValid() {
+		if field.Kind() != reflect.Slice {
+			return fmt.Errorf("<%s> has an unsupported Elements field", n.Data)
+		}
+		elemType := field.Type().Elem()
+		slice := reflect.MakeSlice(field.Type(), 0, len(children))
+		for _, c := range children {
+			e := parseNode(c, n)
+			ev := reflect.ValueOf(e)
+			if !ev.Type().AssignableTo(elemType) {
+				return fmt.Errorf("<%s> cannot contain %T", n.Data, e)
+			}
+			slice = reflect.Append(slice, ev)
+		}
+		field.Set(slice)
+		return nil
+	}
+
+	field := val.FieldByName(elementField)
+	if !field.IsValid() {
+		field = val.FieldByName("TagValue")
+	}
+	if !field.IsValid() {
+		if len(children) > 0 {
+			return fmt.Errorf("<%s> cannot have content", n.Data)
+		}
+		return nil
+	}
+
+	switch {
+	case len(children) == 0:
+		return nil
+	case field.Type() == reflect.TypeOf((*Element)(nil)).Elem():
+		if len(children) != 1 {
+			return fmt.Errorf("<%s> can only contain a single Element", n.Data)
+		}
+		field.Set(reflect.ValueOf(parseNode(children[0], n)))
+		return nil
+	case field.Kind() == reflect.String:
+		text := &strings.Builder{}
+		for _, c := range children {
+			if c.Type != xhtml.TextNode {
+				return fmt.Errorf("<%s> can only contain text", n.Data)
+			}
+			text.WriteString(c.Data)
+		}
+		s := text.String()
+		// A TextElement in an Element field is output without escaping. A TagValue is escaped by its template.
+		if field.Type() == reflect.TypeOf(TextElement("")) && val.FieldByName(elementField).IsValid() {
+			s = template.HTMLEscapeString(s)
+		}
+		field.SetString(s)
+		return nil
+	}
+	return fmt.Errorf("<%s> has unsupported content", n.Data)
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
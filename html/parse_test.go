@@ -0,0 +1,230 @@
+package html
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/kylelemons/godebug/pretty"
+)
+
+func TestParseFragment(t *testing.T) {
+	tests := []struct {
+		desc string
+		html string
+		want []Element
+	}{
+		{
+			desc: "Typed elements with attributes",
+			html: `
+<div id="main" class="box" hidden>
+	<a href="/about" target="_blank">About &amp; more</a>
+	<img src="/logo.png" alt="Logo" width="100">
+</div>`,
+			want: []Element{
+				&Div{
+					GlobalAttrs: GlobalAttrs{ID: "main", Class: "box", Hidden: true},
+					Elements: []Element{
+						&A{Href: URLParse("/about"), Target: "_blank", Elements: []Element{TextElement("About &amp; more")}},
+						TextElement(" "),
+						&Img{Src: URLParse("/logo.png"), Alt: "Logo", WidthPx: 100},
+					},
+				},
+			},
+		},
+		{
+			desc: "Table",
+			html: `<table><tr><th>Name</th><td>Value</td></tr></table>`,
+			want: []Element{
+				&Table{
+					Elements: []TableElement{
+						&TBody{
+							Elements: []*TR{
+								{
+									Elements: []TRElement{
+										&TH{Element: TextElement("Name")},
+										&TD{Element: TextElement("Value")},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "Form",
+			html: `<form action="/submit" method="post"><input type="text" name="q" required><button>Go</button></form>`,
+			want: []Element{
+				&Form{
+					Action: "/submit",
+					Method: "post",
+					Elements: []FormElement{
+						&Input{Type: "text", Name: "q", Required: true},
+						&Button{Elements: []Element{TextElement("Go")}},
+					},
+				},
+			},
+		},
+		{
+			desc: "Unknown tag becomes a Tag",
+			html: `<section id="s" x-y="2"><wbr></section>`,
+			want: []Element{
+				&Tag{
+					GlobalAttrs: GlobalAttrs{ID: "s"},
+					Name:        "section",
+					Attrs:       []Attr{{Name: "x-y", Value: "2"}},
+					Elements:    []Element{&Tag{Name: "wbr", Void: true}},
+				},
+			},
+		},
+		{
+			desc: "Unknown attributes go in GlobalAttrs",
+			html: `<p data-x="1" aria-haspopup="menu" x-y="2">Hi</p>`,
+			want: []Element{
+				&P{
+					GlobalAttrs: GlobalAttrs{
+						Data:   map[string]string{"x": "1"},
+						Aria:   map[string]string{"haspopup": "menu"},
+						Custom: map[string]string{"x-y": "2"},
+					},
+					Elements: []Element{TextElement("Hi")},
+				},
+			},
+		},
+		{
+			desc: "ARIA attributes with invalid values go in Custom",
+			html: `<div role="button" aria-label="Hi" aria-expanded="false"><span role="bogus" aria-hidden="maybe">x</span></div>`,
+			want: []Element{
+				&Div{
+					GlobalAttrs: GlobalAttrs{Role: RoleButton, AriaLabel: "Hi", AriaExpanded: AriaFalse},
+					Elements: []Element{
+						&Span{
+							GlobalAttrs: GlobalAttrs{Custom: map[string]string{"role": "bogus", "aria-hidden": "maybe"}},
+							Elements:    []Element{TextElement("x")},
+						},
+					},
+				},
+			},
+		},
+		{
+			desc: "Script is not escaped",
+			html: `<script>if (a < b) {}</script>`,
+			want: []Element{&Script{TagValue: "if (a < b) {}"}},
+		},
+	}
+
+	for _, test := range tests {
+		got, err := ParseFragment(strings.NewReader(test.html))
+		if err != nil {
+			t.Errorf("TestParseFragment(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("TestParseFragment(%s): -want/+got:\n%s", test.desc, diff)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	doc, err := Parse(strings.NewReader(`<!DOCTYPE html>
+<html lang="en">
+<head>
+	<meta charset="UTF-8">
+	<title>A &amp; B</title>
+</head>
+<body class="page">
+	<!-- comments are removed -->
+	<p>Hello</p>
+</body>
+</html>`))
+	if err != nil {
+		t.Fatalf("TestParse: got err == %s, want err == nil", err)
+	}
+
+	want := &Doc{
+		GlobalAttrs: GlobalAttrs{Lang: "en"},
+		Head: &Head{
+			Elements: []Element{
+				&Meta{Charset: "UTF-8"},
+				&Title{TagValue: "A & B"},
+			},
+		},
+		Body: &Body{
+			GlobalAttrs: GlobalAttrs{Class: "page"},
+			Elements:    []Element{&P{Elements: []Element{TextElement("Hello")}}},
+		},
+	}
+	if diff := pretty.Compare(want, doc); diff != "" {
+		t.Errorf("TestParse: -want/+got:\n%s", diff)
+	}
+
+	if err := doc.Init(); err != nil {
+		t.Fatalf("TestParse: Doc.Init(): got err == %s, want err == nil", err)
+	}
+	buff := &strings.Builder{}
+	if err := doc.Execute(context.Background(), buff, nil); err != nil {
+		t.Fatalf("TestParse: Doc.Execute(): got err == %s, want err == nil", err)
+	}
+	if !strings.Contains(buff.String(), "<title >A &amp; B</title>") {
+		t.Errorf("TestParse: Doc.Execute() output did not contain the escaped title:\n%s", buff.String())
+	}
+}
+
+func TestParseWhitespace(t *testing.T) {
+	tests := []struct {
+		desc string
+		html string
+		want []Element
+	}{
+		{
+			desc: "Space between inline elements is kept",
+			html: "<p><b>a</b> \n <i>b</i></p>",
+			want: []Element{
+				&P{Elements: []Element{
+					&Tag{Name: "b", Elements: []Element{TextElement("a")}},
+					TextElement(" "),
+					&I{Element: TextElement("b")},
+				}},
+			},
+		},
+		{
+			desc: "Space between block elements is dropped",
+			html: "<div>\n\t<p>a</p>\n\t<p>b</p>\n</div>",
+			want: []Element{
+				&Div{Elements: []Element{
+					&P{Elements: []Element{TextElement("a")}},
+					&P{Elements: []Element{TextElement("b")}},
+				}},
+			},
+		},
+	}
+
+	tags := regexp.MustCompile(`<[^>]*>`)
+	space := regexp.MustCompile(`\s+`)
+	text := func(s string) string {
+		return strings.TrimSpace(space.ReplaceAllString(tags.ReplaceAllString(s, ""), " "))
+	}
+
+	for _, test := range tests {
+		got, err := ParseFragment(strings.NewReader(test.html))
+		if err != nil {
+			t.Errorf("TestParseWhitespace(%s): got err == %s, want err == nil", test.desc, err)
+			continue
+		}
+		if diff := pretty.Compare(test.want, got); diff != "" {
+			t.Errorf("TestParseWhitespace(%s): -want/+got:\n%s", test.desc, diff)
+		}
+
+		// The rendered Elements must show the same text as the input.
+		buff := &strings.Builder{}
+		pipe := NewPipeline(context.Background(), nil, buff)
+		for _, e := range got {
+			e.Execute(pipe)
+		}
+		if text(buff.String()) != text(test.html) {
+			t.Errorf("TestParseWhitespace(%s): rendered text is %q, want %q", test.desc, text(buff.String()), text(test.html))
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package html
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+var slotTmpl = template.Must(template.New("slot").Parse(strings.TrimSpace(`
+<slot {{.Self.Attr}} {{.Self.GlobalAttrs.Attr}} {{.Self.Events.Attr}}>
+	{{- $data := .}}
+	{{- range .Self.Elements}}
+	{{.Execute $data}}
+	{{- end}}
+</slot>
+`)))
+
+// Slot is a placeholder inside a component.Gear's Doc that is filled with content placed inside the
+// html.Component tag (the light DOM). A Slot without a Name is the default slot and receives all content
+// that does not have a GlobalAttrs.Slot value. A Slot with a Name receives content whose GlobalAttrs.Slot
+// matches the Name.
+type Slot struct {
+	GlobalAttrs
+	Events *Events
+
+	// Name is the name of the slot. Content is assigned to this slot by setting GlobalAttrs.Slot to this value.
+	Name string
+
+	// Elements are displayed if no content is assigned to the slot.
+	Elements []Element
+}
+
+func (s *Slot) validate() error {
+	if strings.ContainsAny(s.Name, " \t\n\"'") {
+		return fmt.Errorf("Slot.Name(%q) cannot contain spaces or quotes", s.Name)
+	}
+	return nil
+}
+
+func (s *Slot) Attr() template.HTMLAttr {
+	output := structToString(s)
+	return template.HTMLAttr(output)
+}
+
+func (s *Slot) Execute(pipe Pipeline) string {
+	pipe.Self = s
+
+	if err := slotTmpl.Execute(pipe.W, pipe); err != nil {
+		panic(err)
+	}
+
+	return EmptyString
+}
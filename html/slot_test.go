@@ -0,0 +1,47 @@
+package html
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestSlot(t *testing.T) {
+	tests := []struct {
+		desc string
+		slot *Slot
+		want string
+	}{
+		{
+			desc: "Default slot",
+			slot: &Slot{},
+			want: "<slot   >\n</slot>",
+		},
+		{
+			desc: "All attributes + 1 global + 1 event + 1 element",
+			slot: &Slot{
+				GlobalAttrs: GlobalAttrs{
+					AccessKey: "key",
+				},
+				Name:     "title",
+				Events:   (&Events{}).AddScript(OnError, "handleError"),
+				Elements: []Element{TextElement("Untitled")},
+			},
+
+			want: strings.TrimSpace(`
+<slot name="title" accesskey="key" onerror="handleError">
+	Untitled
+</slot>
+`),
+		},
+	}
+
+	for _, test := range tests {
+		got := &strings.Builder{}
+		pipe := NewPipeline(context.Background(), nil, got)
+		test.slot.Execute(pipe)
+		if test.want != got.String() {
+			t.Errorf("TestSlot(%s): \n\tgot  %q\n\twant %q", test.desc, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package html
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Tag represents any HTML tag that does not have its own type in this package, such as <section> or <pre>.
+// It is also what Parse() uses for tags it cannot represent with a more specific type. A Tag can be
+// used anywhere an Element is accepted, including inside a Form, Select or Table. Like other Elements,
+// a Tag can have an ID and Events, so it works with Walker(), GetElementID() and DocUpdater.
+type Tag struct {
+	GlobalAttrs
+
+	Events *Events
+
+	// Name is the name of the tag, such as "section". It must start with an ascii letter and contain only
+	// ascii letters, numbers, hyphens, underscores and periods.
+	Name string
+	// Attrs are attributes of the tag that are not in GlobalAttrs. They are output in order after GlobalAttrs.
+	Attrs []Attr
+	// Elements are the Elements inside the tag.
+	Elements []Element
+	// Void indicates the tag has no end tag, such as <wbr>. A Void tag cannot have Elements.
+	Void bool
+}
+
+func (t *Tag) validate() error {
+	if err := validTagName(t.Name); err != nil {
+		return fmt.Errorf("Tag(%s): %w", t.Name, err)
+	}
+	if t.Void && len(t.Elements) > 0 {
+		return fmt.Errorf("Tag(%s) is Void but has Elements", t.Name)
+	}
+	for _, a := range t.Attrs {
+		if err := validAttrName(a.Name); err != nil {
+			return fmt.Errorf("Tag(%s): %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+// validTagName validates that name can be used as the name of a Tag.
+func validTagName(name string) error {
+	if name == "" {
+		return fmt.Errorf("name cannot be empty")
+	}
+	for i, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i == 0:
+			return fmt.Errorf("name must start with an ascii letter")
+		case r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return fmt.Errorf("name cannot contain %q", r)
+		}
+	}
+	return nil
+}
+
+func (t *Tag) isFormElement()     {}
+func (t *Tag) isSelectElement()   {}
+func (t *Tag) isOptGroupElement() {}
+func (t *Tag) isTableElement()    {}
+func (t *Tag) isTRElement()       {}
+func (t *Tag) isColGroupElement() {}
+
+func (t *Tag) Execute(pipe Pipeline) string {
+	pipe.Self = t
+
+	if err := t.validate(); err != nil {
+		pipe.Error(err)
+		return EmptyString
+	}
+
+	attrs := []string{}
+	if ga := string(t.GlobalAttrs.Attr()); ga != "" {
+		attrs = append(attrs, ga)
+	}
+	if ev := string(t.Events.Attr()); ev != "" {
+		attrs = append(attrs, ev)
+	}
+	for _, a := range t.Attrs {
+		attrs = append(attrs, a.String())
+	}
+
+	pipe.W.Write([]byte("<" + t.Name))
+	if len(attrs) > 0 {
+		pipe.W.Write([]byte(" " + strings.Join(attrs, " ")))
+	}
+	pipe.W.Write([]byte(">"))
+	if t.Void {
+		return EmptyString
+	}
+
+	for _, e := range t.Elements {
+		e.Execute(pipe)
+	}
+	pipe.W.Write([]byte("</" + t.Name + ">"))
+	return EmptyString
+}
@@ -0,0 +1,134 @@
+package html
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestTag(t *testing.T) {
+	tests := []struct {
+		desc string
+		tag  *Tag
+		want string
+	}{
+		{
+			desc: "With attributes and elements",
+			tag: &Tag{
+				Name:     "section",
+				Attrs:    []Attr{{Name: "data-id", Value: `a"b`}, {Name: "inert"}},
+				Elements: []Element{TextElement("hello")},
+			},
+			want: `<section data-id="a&#34;b" inert>hello</section>`,
+		},
+		{
+			desc: "With GlobalAttrs and Events",
+			tag: &Tag{
+				GlobalAttrs: GlobalAttrs{ID: "id", Data: map[string]string{"x": "1"}},
+				Events:      (&Events{}).AddScript(OnClick, "go()"),
+				Name:        "my-widget",
+				Attrs:       []Attr{{Name: "mode", Value: "dark"}},
+			},
+			want: `<my-widget id="id" data-x="1" onclick="go()" mode="dark"></my-widget>`,
+		},
+		{
+			desc: "Void",
+			tag:  &Tag{Name: "wbr", Void: true},
+			want: `<wbr>`,
+		},
+	}
+
+	for _, test := range tests {
+		buff := &strings.Builder{}
+		pipe := NewPipeline(context.Background(), nil, buff)
+
+		test.tag.Execute(pipe)
+		if got := buff.String(); got != test.want {
+			t.Errorf("TestTag(%s): got %q, want %q", test.desc, got, test.want)
+		}
+	}
+}
+
+func TestTagValidate(t *testing.T) {
+	tests := []struct {
+		desc    string
+		tag     *Tag
+		wantErr bool
+	}{
+		{desc: "Valid", tag: &Tag{Name: "x-foo.bar_1"}},
+		{desc: "Empty name", tag: &Tag{}, wantErr: true},
+		{desc: "Name starts with a number", tag: &Tag{Name: "1a"}, wantErr: true},
+		{desc: "Name with a space", tag: &Tag{Name: "a b"}, wantErr: true},
+		{desc: "Void with Elements", tag: &Tag{Name: "br", Void: true, Elements: []Element{TextElement("a")}}, wantErr: true},
+		{desc: "Invalid attribute", tag: &Tag{Name: "a", Attrs: []Attr{{Name: "a>"}}}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := test.tag.validate()
+		switch {
+		case err == nil && test.wantErr:
+			t.Errorf("TestTagValidate(%s): got err == nil, want err != nil", test.desc)
+		case err != nil && !test.wantErr:
+			t.Errorf("TestTagValidate(%s): got err == %s, want err == nil", test.desc, err)
+		}
+	}
+}
+
+func TestTagWalker(t *testing.T) {
+	inner := &Tag{GlobalAttrs: GlobalAttrs{ID: "inner"}, Name: "section"}
+	root := &Tag{Name: "main", Elements: []Element{inner}}
+
+	var found Element
+	for walked := range Walker(context.Background(), root) {
+		if GetElementID(walked.Element) == "inner" {
+			found = walked.Element
+		}
+	}
+	if found != inner {
+		t.Errorf("TestTagWalker: Walker() and GetElementID() did not find the inner Tag")
+	}
+}
+
+func TestTagRenderErrors(t *testing.T) {
+	tests := []struct {
+		desc string
+		elem Element
+	}{
+		{desc: "Invalid Tag", elem: &Tag{Name: "bad name"}},
+		{
+			desc: "Dynamic returns an invalid Tag",
+			elem: Dynamic(func(pipe Pipeline) []Element { return []Element{&Tag{Name: "bad name"}} }),
+		},
+		{
+			desc: "Dynamic returns an invalid GlobalAttrs",
+			elem: Dynamic(func(pipe Pipeline) []Element {
+				return []Element{&Div{GlobalAttrs: GlobalAttrs{Custom: map[string]string{"a b": "c"}}}}
+			}),
+		},
+		{
+			desc: "Dynamic returns an invalid GlobalAttrs below another Element",
+			elem: Dynamic(func(pipe Pipeline) []Element {
+				return []Element{&Div{Elements: []Element{&Span{GlobalAttrs: GlobalAttrs{Role: "bogus"}}}}}
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		doc := &Doc{Head: &Head{}, Body: &Body{Elements: []Element{test.elem}}}
+		if _, ok := test.elem.(*Tag); ok {
+			// Init() would catch this, so render it directly as a DocUpdater or Gear could.
+			pipe := NewPipeline(context.Background(), nil, &strings.Builder{})
+			test.elem.Execute(pipe)
+			if pipe.HadError() == nil {
+				t.Errorf("TestTagRenderErrors(%s): got err == nil, want err != nil", test.desc)
+			}
+			continue
+		}
+		if err := doc.Init(); err != nil {
+			t.Fatalf("TestTagRenderErrors(%s): Init() got err == %s, want err == nil", test.desc, err)
+		}
+		if err := doc.Execute(context.Background(), &strings.Builder{}, nil); err == nil {
+			t.Errorf("TestTagRenderErrors(%s): got err == nil, want err != nil", test.desc)
+		}
+	}
+}
@@ -1,7 +1,9 @@
 package main
 
 import (
+	"context"
 	"flag"
+	"log"
 	"strings"
 
 	"github.com/johnsiilver/webgear/component/viewer"
@@ -27,12 +29,17 @@ func main() {
 		panic(err)
 	}
 
-	v := viewer.New(
-		8080,
+	v, err := viewer.New(
+		*port,
 		list,
 		viewer.BackgroundColor("white"),
 		viewer.ServeOtherFiles("../../../", []string{".css", ".jpg", ".svg", ".png"}),
 	)
+	if err != nil {
+		panic(err)
+	}
 
-	v.Run()
+	if err := v.Run(context.Background()); err != nil {
+		log.Fatal(err)
+	}
 }
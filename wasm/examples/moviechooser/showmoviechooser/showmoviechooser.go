@@ -1,12 +1,11 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
 	"net/url"
-	"time"
 
 	"github.com/johnsiilver/webgear/handlers"
 	httpHandler "github.com/johnsiilver/webgear/wasm/http"
@@ -28,18 +27,10 @@ func main() {
 
 	h := handlers.New(handlers.DoNotCache())
 
-	server := &http.Server{
-		Addr:           fmt.Sprintf("127.0.0.1:%d", *port),
-		Handler:        h.ServerMux(),
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
-	}
-
 	h.ServeFilesFrom("../", "", []string{".css", ".wasm"})
 	h.HTTPHandler("/", movieChooserHandler)
 
-	log.Printf("http server serving on :%d", *port)
-
-	log.Fatal(server.ListenAndServe())
+	if err := handlers.Serve(context.Background(), h, handlers.Addr(fmt.Sprintf("127.0.0.1:%d", *port))); err != nil {
+		log.Fatal(err)
+	}
 }
@@ -9,7 +9,6 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"time"
 
 	"github.com/johnsiilver/webgear/handlers"
 
@@ -81,22 +80,15 @@ func setupWASM(addr, restAddr string) {
 	}
 
 	h := handlers.New(handlers.DoNotCache())
-	server := &http.Server{
-		Addr:           addr,
-		Handler:        h.ServerMux(),
-		ReadTimeout:    10 * time.Second,
-		WriteTimeout:   10 * time.Second,
-		MaxHeaderBytes: 1 << 20,
-	}
 
 	// Serve all .css and .wasm files.
 	h.ServeFilesFrom("", "", []string{".css", ".wasm", ".svg"})
 	// Serve our snippet app from /.
 	h.HTTPHandler("/", snippetsHandler)
 
-	log.Printf("http server serving on at %s", addr)
-
-	go log.Fatal(server.ListenAndServe())
+	if err := handlers.Serve(context.Background(), h, handlers.Addr(addr)); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func main() {
@@ -22,13 +22,32 @@ func (h handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	h.doc.Execute(r.Context(), w, r)
 }
 
+type options struct {
+	head []html.Element
+}
+
+// Option is an optional argument to Handler().
+type Option func(o *options)
+
+// HeadElements adds Elements to the <head> of the page, after the script that loads the WASM application.
+func HeadElements(elements ...html.Element) Option {
+	return func(o *options) {
+		o.head = append(o.head, elements...)
+	}
+}
+
 // Handler will return a Handler that will return code to load your WASM application.
-func Handler(downloadAppFrom *url.URL) (http.Handler, error) {
+func Handler(downloadAppFrom *url.URL, opts ...Option) (http.Handler, error) {
 	p := downloadAppFrom.String()
 	if p == "" {
 		return nil, fmt.Errorf("the url passed(%s) to wasm.Handler was invalid", p)
 	}
 
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
 	doc := &html.Doc{
 		Head: &html.Head{
 			Elements: []html.Element{
@@ -50,6 +69,7 @@ WebAssembly.instantiateStreaming(fetch("%s"), go.importObject).then((result) =>
 		},
 		Body: &html.Body{},
 	}
+	doc.Head.Elements = append(doc.Head.Elements, o.head...)
 	if err := doc.Init(); err != nil {
 		panic(err)
 	}